@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"math/rand"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/meinside/google-ddns-updater/helper"
+)
+
+// daemonJitterRatio is the maximum fraction of the interval added/subtracted
+// at random before each check, so periodic hits against the ip-checking and
+// ddns-update services don't look abusively regular
+const daemonJitterRatio = 0.1
+
+// runDaemon keeps the process alive, re-checking the external ip on every
+// `interval` (± jitter) and updating `hostnames` (or, if empty, every
+// hostname in the loaded configs) when it has changed. `confFilepath` is
+// reloaded on SIGHUP; SIGTERM/SIGINT trigger a graceful shutdown.
+func runDaemon(ctx context.Context, confFilepath string, hostnames []string, cacheDir string, interval time.Duration) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGHUP, syscall.SIGTERM, syscall.SIGINT)
+	defer signal.Stop(sigs)
+
+	confs, err := helper.ReadConfigs(confFilepath)
+	if err != nil {
+		helper.ExitWithError("failed to read configs file at: %s", confFilepath)
+	}
+
+	helper.Infof("daemon started, checking every %s", interval)
+
+	timer := time.NewTimer(jitteredInterval(interval))
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			tickHostnames := hostnames
+			if len(tickHostnames) <= 0 {
+				for _, conf := range confs.Configs {
+					tickHostnames = append(tickHostnames, conf.Hostname)
+				}
+			}
+
+			if ipAddr, ipErr := helper.GetExternalIP(ctx); ipErr == nil {
+				if updateErr := updateHostnames(ctx, confs, tickHostnames, cacheDir, ipAddr); updateErr != nil {
+					helper.Errorf("daemon update failed: %s", updateErr)
+				}
+			} else {
+				helper.Errorf("daemon failed to fetch external ip: %s", ipErr)
+			}
+
+			timer.Reset(jitteredInterval(interval))
+
+		case sig := <-sigs:
+			switch sig {
+			case syscall.SIGHUP:
+				helper.Infof("reloading configs file at: %s", confFilepath)
+
+				if reloaded, reloadErr := helper.ReadConfigs(confFilepath); reloadErr == nil {
+					confs = reloaded
+
+					if reloadedNotifier, notifierErr := helper.BuildNotifier(confs); notifierErr == nil {
+						helper.SetNotifier(reloadedNotifier)
+					} else {
+						helper.Errorf("failed to rebuild notifier from reloaded configs: %s", notifierErr)
+					}
+				} else {
+					helper.Errorf("failed to reload configs file: %s", reloadErr)
+				}
+
+			case syscall.SIGTERM, syscall.SIGINT:
+				helper.Infof("shutting down daemon")
+
+				helper.WaitForNotifications()
+
+				return
+
+			default:
+				// ignore
+			}
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// jitteredInterval returns `interval` adjusted by a random ± daemonJitterRatio factor
+func jitteredInterval(interval time.Duration) time.Duration {
+	jitter := float64(interval) * daemonJitterRatio
+	offset := (rand.Float64()*2 - 1) * jitter
+
+	return interval + time.Duration(offset)
+}
@@ -0,0 +1,43 @@
+package helper
+
+import "fmt"
+
+// CacheBackend persists the last-known ip address per hostname/family, so
+// repeated runs (and daemon ticks) can tell whether a dns record is already
+// up to date without re-querying the DDNS provider
+type CacheBackend interface {
+	// Load returns the cached ip address for conf's hostname and family,
+	// or fallbackIP if nothing has been cached yet
+	Load(cacheDir string, conf Config, family IPFamily) (string, error)
+
+	// Store records ip as the latest cached address for conf's hostname and family
+	Store(cacheDir string, conf Config, family IPFamily, ip string) error
+
+	// CompareAndUpdate loads the cached ip for conf's hostname and family and,
+	// if it differs from wantIP, calls apply(oldIP) and stores wantIP only if
+	// apply succeeds. The entire read-compare-apply-write sequence runs under
+	// one lock acquisition, so two overlapping invocations (cron + manual run,
+	// or two daemon ticks) can't both observe the same stale cached ip and
+	// both push a duplicate update to the DDNS provider.
+	CompareAndUpdate(cacheDir string, conf Config, family IPFamily, wantIP string, apply func(oldIP string) error) (changed bool, oldIP string, err error)
+}
+
+// cacheBackend is the backend used by LoadCachedIP / cacheIP; switch it with
+// SetCacheBackend (eg. from the `--cache-backend` flag)
+var cacheBackend CacheBackend = fileCacheBackend{}
+
+// SetCacheBackend selects the backend used for ip caching: "file" (the
+// default, one flat file per hostname/family) or "sqlite" (a single
+// database file retaining cache history)
+func SetCacheBackend(kind string) error {
+	switch kind {
+	case "", "file":
+		cacheBackend = fileCacheBackend{}
+	case "sqlite":
+		cacheBackend = newSQLiteCacheBackend()
+	default:
+		return fmt.Errorf("unknown cache backend: %s (want \"file\" or \"sqlite\")", kind)
+	}
+
+	return nil
+}
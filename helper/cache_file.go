@@ -0,0 +1,108 @@
+package helper
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// fileCacheBackend is the default CacheBackend: one flat file per
+// hostname/family, written atomically (write to a `.tmp` file then
+// os.Rename) and guarded by an OS-level advisory lock held for the whole
+// read-compare-write critical section, so concurrent daemon ticks or
+// overlapping invocations (cron + manual run) can't corrupt or lose it
+type fileCacheBackend struct{}
+
+// ip cache file path, and its companion lock file
+func ipCacheFilepath(cacheDir, hostname string, family IPFamily) string {
+	return filepath.Join(cacheDir, fmt.Sprintf("%s.%s.%s", ipCacheFilename, hostname, family))
+}
+func ipCacheLockFilepath(cacheDir, hostname string, family IPFamily) string {
+	return ipCacheFilepath(cacheDir, hostname, family) + ".lock"
+}
+
+func (fileCacheBackend) Load(cacheDir string, conf Config, family IPFamily) (string, error) {
+	path := ipCacheFilepath(cacheDir, conf.Hostname, family)
+
+	unlock, err := lockFile(ipCacheLockFilepath(cacheDir, conf.Hostname, family))
+	if err != nil {
+		return "", fmt.Errorf("failed to lock ip cache file: %s: %s", path, err)
+	}
+	defer unlock()
+
+	if _, err = os.Stat(path); err != nil && os.IsNotExist(err) {
+		logger.Infof("ip cache file: %s does not exist", path)
+
+		_ = atomicWriteFile(path, []byte(fallbackIP))
+
+		return fallbackIP, nil
+	}
+
+	var data []byte
+	data, err = os.ReadFile(path)
+
+	if err == nil {
+		logger.Debugf("loaded cached ip: %s from file: %s", string(data), path)
+	}
+
+	return string(data), err
+}
+
+func (fileCacheBackend) Store(cacheDir string, conf Config, family IPFamily, ip string) error {
+	path := ipCacheFilepath(cacheDir, conf.Hostname, family)
+
+	unlock, err := lockFile(ipCacheLockFilepath(cacheDir, conf.Hostname, family))
+	if err != nil {
+		return fmt.Errorf("failed to lock ip cache file: %s: %s", path, err)
+	}
+	defer unlock()
+
+	logger.Debugf("caching ip: %s to file: %s", ip, path)
+
+	return atomicWriteFile(path, []byte(ip))
+}
+
+func (fileCacheBackend) CompareAndUpdate(cacheDir string, conf Config, family IPFamily, wantIP string, apply func(oldIP string) error) (changed bool, oldIP string, err error) {
+	path := ipCacheFilepath(cacheDir, conf.Hostname, family)
+
+	unlock, err := lockFile(ipCacheLockFilepath(cacheDir, conf.Hostname, family))
+	if err != nil {
+		return false, "", fmt.Errorf("failed to lock ip cache file: %s: %s", path, err)
+	}
+	defer unlock()
+
+	data, statErr := os.ReadFile(path)
+	switch {
+	case statErr == nil:
+		oldIP = string(data)
+	case os.IsNotExist(statErr):
+		logger.Infof("ip cache file: %s does not exist", path)
+		oldIP = fallbackIP
+	default:
+		return false, "", statErr
+	}
+
+	if oldIP == wantIP {
+		return false, oldIP, nil
+	}
+
+	if err = apply(oldIP); err != nil {
+		return false, oldIP, err
+	}
+
+	logger.Debugf("caching ip: %s to file: %s", wantIP, path)
+
+	return true, oldIP, atomicWriteFile(path, []byte(wantIP))
+}
+
+// atomicWriteFile writes data to a sibling `.tmp` file and renames it into
+// place, so readers never observe a partially-written cache file
+func atomicWriteFile(path string, data []byte) error {
+	tmp := path + ".tmp"
+
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,164 @@
+package helper
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	_ "modernc.org/sqlite"
+)
+
+// sqliteCacheFilename is the database file created under a cache dir when
+// `--cache-backend sqlite` is selected
+const sqliteCacheFilename = "ip.cache.db"
+
+// sqliteCacheBackend stores cached ips (and their full history) in a single
+// sqlite database per cache dir, for users who'd rather query one file than
+// scatter per-hostname cache files around
+type sqliteCacheBackend struct {
+	mu     sync.Mutex
+	dbs    map[string]*sql.DB     // cacheDir -> opened db
+	keyMus map[string]*sync.Mutex // cacheDir|hostname|family -> CompareAndUpdate critical-section lock
+}
+
+func newSQLiteCacheBackend() *sqliteCacheBackend {
+	return &sqliteCacheBackend{dbs: map[string]*sql.DB{}, keyMus: map[string]*sync.Mutex{}}
+}
+
+// keyLock returns the dedicated mutex for a single hostname/family's
+// critical section, so CompareAndUpdate only serializes against itself for
+// the same hostname/family, not every hostname sharing the same db
+func (b *sqliteCacheBackend) keyLock(key string) *sync.Mutex {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	mu, exists := b.keyMus[key]
+	if !exists {
+		mu = &sync.Mutex{}
+		b.keyMus[key] = mu
+	}
+
+	return mu
+}
+
+func (b *sqliteCacheBackend) db(cacheDir string) (*sql.DB, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if db, exists := b.dbs[cacheDir]; exists {
+		return db, nil
+	}
+
+	path := filepath.Join(cacheDir, sqliteCacheFilename)
+
+	db, err := sql.Open("sqlite", path+"?_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, err
+	}
+
+	// single connection: sqlite serializes writers anyway, and this avoids
+	// SQLITE_BUSY errors from this process's own goroutines racing each other
+	db.SetMaxOpenConns(1)
+
+	if _, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS ip_cache_history (
+			id         INTEGER PRIMARY KEY AUTOINCREMENT,
+			hostname   TEXT NOT NULL,
+			family     TEXT NOT NULL,
+			ip         TEXT NOT NULL,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)
+	`); err != nil {
+		_ = db.Close()
+
+		return nil, fmt.Errorf("failed to initialize sqlite cache db: %s", err)
+	}
+
+	b.dbs[cacheDir] = db
+
+	return db, nil
+}
+
+func (b *sqliteCacheBackend) Load(cacheDir string, conf Config, family IPFamily) (string, error) {
+	db, err := b.db(cacheDir)
+	if err != nil {
+		return "", err
+	}
+
+	var ip string
+	row := db.QueryRow(`
+		SELECT ip FROM ip_cache_history
+		WHERE hostname = ? AND family = ?
+		ORDER BY id DESC LIMIT 1
+	`, conf.Hostname, string(family))
+
+	switch err = row.Scan(&ip); err {
+	case nil:
+		logger.Debugf("loaded cached ip: %s from sqlite cache for hostname: %s", ip, conf.Hostname)
+
+		return ip, nil
+
+	case sql.ErrNoRows:
+		logger.Infof("no cached ip in sqlite cache yet for hostname: %s", conf.Hostname)
+
+		return fallbackIP, b.Store(cacheDir, conf, family, fallbackIP)
+
+	default:
+		return "", err
+	}
+}
+
+func (b *sqliteCacheBackend) Store(cacheDir string, conf Config, family IPFamily, ip string) error {
+	db, err := b.db(cacheDir)
+	if err != nil {
+		return err
+	}
+
+	logger.Debugf("caching ip: %s to sqlite cache for hostname: %s", ip, conf.Hostname)
+
+	_, err = db.Exec(`
+		INSERT INTO ip_cache_history (hostname, family, ip) VALUES (?, ?, ?)
+	`, conf.Hostname, string(family), ip)
+
+	return err
+}
+
+// sqliteCacheLockFilepath is the OS-level advisory lock file guarding
+// CompareAndUpdate's critical section for a single hostname/family, sibling
+// to the db itself (mirrors ipCacheLockFilepath in cache_file.go)
+func sqliteCacheLockFilepath(cacheDir, hostname string, family IPFamily) string {
+	return filepath.Join(cacheDir, fmt.Sprintf("%s.%s.%s.lock", sqliteCacheFilename, hostname, family))
+}
+
+func (b *sqliteCacheBackend) CompareAndUpdate(cacheDir string, conf Config, family IPFamily, wantIP string, apply func(oldIP string) error) (changed bool, oldIP string, err error) {
+	key := cacheDir + "|" + conf.Hostname + "|" + string(family)
+
+	// in-process: avoid two goroutines of this process racing the OS lock below
+	mu := b.keyLock(key)
+	mu.Lock()
+	defer mu.Unlock()
+
+	// cross-process: sqlite's own locking only covers individual statements,
+	// not this whole read-compare-apply-write sequence, so two separate
+	// processes (cron + manual run) still need an external OS lock
+	unlock, err := lockFile(sqliteCacheLockFilepath(cacheDir, conf.Hostname, family))
+	if err != nil {
+		return false, "", fmt.Errorf("failed to lock sqlite ip cache: %s", err)
+	}
+	defer unlock()
+
+	if oldIP, err = b.Load(cacheDir, conf, family); err != nil {
+		return false, "", err
+	}
+
+	if oldIP == wantIP {
+		return false, oldIP, nil
+	}
+
+	if err = apply(oldIP); err != nil {
+		return false, oldIP, err
+	}
+
+	return true, oldIP, b.Store(cacheDir, conf, family, wantIP)
+}
@@ -0,0 +1,116 @@
+package helper
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/zalando/go-keyring"
+)
+
+// encryptedConfigPrefix tags an at-rest-encrypted config file, so
+// ReadConfigs knows to decrypt it before unmarshalling
+const encryptedConfigPrefix = "GDDNS1:"
+
+// keyring entry used as a fallback for the config encryption passphrase
+// when the GOOGLE_DDNS_KEY environment variable is not set
+const (
+	configEncryptionKeyringService = "google-ddns-updater"
+	configEncryptionKeyringAccount = "config-key"
+)
+
+// configEncryptionPassphrase resolves the passphrase used to encrypt/decrypt
+// config.json: the GOOGLE_DDNS_KEY environment variable, falling back to a keyring entry
+func configEncryptionPassphrase() (string, error) {
+	if key := os.Getenv("GOOGLE_DDNS_KEY"); key != "" {
+		return key, nil
+	}
+
+	key, err := keyring.Get(configEncryptionKeyringService, configEncryptionKeyringAccount)
+	if err != nil {
+		return "", fmt.Errorf("no GOOGLE_DDNS_KEY set, and no keyring entry found: %s", err)
+	}
+
+	return key, nil
+}
+
+// encryptionKeyFromPassphrase derives a fixed-size AES-256 key from an arbitrary-length passphrase
+func encryptionKeyFromPassphrase(passphrase string) []byte {
+	sum := sha256.Sum256([]byte(passphrase))
+
+	return sum[:]
+}
+
+// EncryptConfig reads the plaintext config file at plainPath and writes an
+// AES-GCM-encrypted copy to encryptedPath, for the `encrypt-config` subcommand
+func EncryptConfig(plainPath, encryptedPath string) error {
+	passphrase, err := configEncryptionPassphrase()
+	if err != nil {
+		return err
+	}
+
+	plaintext, err := os.ReadFile(plainPath)
+	if err != nil {
+		return err
+	}
+
+	block, err := aes.NewCipher(encryptionKeyFromPassphrase(passphrase))
+	if err != nil {
+		return err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return err
+	}
+
+	ciphertext := gcm.Seal(nonce, nonce, plaintext, nil)
+	encoded := encryptedConfigPrefix + base64.StdEncoding.EncodeToString(ciphertext)
+
+	return os.WriteFile(encryptedPath, []byte(encoded), 0600)
+}
+
+// decryptConfig decrypts an at-rest-encrypted config file's raw contents
+func decryptConfig(data []byte) ([]byte, error) {
+	encoded := strings.TrimPrefix(string(data), encryptedConfigPrefix)
+
+	ciphertext, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode encrypted config: %s", err)
+	}
+
+	passphrase, err := configEncryptionPassphrase()
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(encryptionKeyFromPassphrase(passphrase))
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, errors.New("encrypted config is too short")
+	}
+
+	nonce, ciphertext := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
@@ -0,0 +1,68 @@
+package helper
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestEncryptConfigRoundTrip(t *testing.T) {
+	t.Setenv("GOOGLE_DDNS_KEY", "test-passphrase")
+
+	dir := t.TempDir()
+	plainPath := dir + "/config.json"
+	encryptedPath := dir + "/config.json.enc"
+
+	plaintext := []byte(`{"configs":[{"hostname":"sub.example.com"}]}`)
+	if err := os.WriteFile(plainPath, plaintext, 0600); err != nil {
+		t.Fatalf("failed to write plaintext config: %s", err)
+	}
+
+	if err := EncryptConfig(plainPath, encryptedPath); err != nil {
+		t.Fatalf("EncryptConfig() error: %s", err)
+	}
+
+	encrypted, err := os.ReadFile(encryptedPath)
+	if err != nil {
+		t.Fatalf("failed to read encrypted config: %s", err)
+	}
+
+	if !bytes.HasPrefix(encrypted, []byte(encryptedConfigPrefix)) {
+		t.Fatalf("encrypted config missing prefix: %s", encryptedConfigPrefix)
+	}
+
+	decrypted, err := decryptConfig(encrypted)
+	if err != nil {
+		t.Fatalf("decryptConfig() error: %s", err)
+	}
+
+	if !bytes.Equal(decrypted, plaintext) {
+		t.Errorf("decryptConfig() = %q, want: %q", decrypted, plaintext)
+	}
+}
+
+func TestDecryptConfigWrongPassphrase(t *testing.T) {
+	t.Setenv("GOOGLE_DDNS_KEY", "correct-passphrase")
+
+	dir := t.TempDir()
+	plainPath := dir + "/config.json"
+	encryptedPath := dir + "/config.json.enc"
+
+	if err := os.WriteFile(plainPath, []byte(`{}`), 0600); err != nil {
+		t.Fatalf("failed to write plaintext config: %s", err)
+	}
+	if err := EncryptConfig(plainPath, encryptedPath); err != nil {
+		t.Fatalf("EncryptConfig() error: %s", err)
+	}
+
+	encrypted, err := os.ReadFile(encryptedPath)
+	if err != nil {
+		t.Fatalf("failed to read encrypted config: %s", err)
+	}
+
+	t.Setenv("GOOGLE_DDNS_KEY", "wrong-passphrase")
+
+	if _, err = decryptConfig(encrypted); err == nil {
+		t.Fatal("expected an error decrypting with the wrong passphrase, got nil")
+	}
+}
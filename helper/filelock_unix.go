@@ -0,0 +1,29 @@
+//go:build !windows
+
+package helper
+
+import (
+	"os"
+	"syscall"
+)
+
+// lockFile opens (creating if necessary) the lock file at path and takes an
+// exclusive advisory `flock`, blocking until it is acquired. The returned
+// func releases the lock and closes the file; callers must call it exactly once.
+func lockFile(path string) (unlock func(), err error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		_ = f.Close()
+
+		return nil, err
+	}
+
+	return func() {
+		_ = syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+		_ = f.Close()
+	}, nil
+}
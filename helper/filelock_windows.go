@@ -0,0 +1,56 @@
+//go:build windows
+
+package helper
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	modkernel32      = syscall.NewLazyDLL("kernel32.dll")
+	procLockFileEx   = modkernel32.NewProc("LockFileEx")
+	procUnlockFileEx = modkernel32.NewProc("UnlockFileEx")
+)
+
+const lockfileExclusiveLock = 0x2
+
+// lockFile opens (creating if necessary) the lock file at path and takes an
+// exclusive advisory lock via `LockFileEx`, blocking until it is acquired.
+// The returned func releases the lock and closes the file; callers must
+// call it exactly once.
+func lockFile(path string) (unlock func(), err error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	overlapped := new(syscall.Overlapped)
+
+	// LockFileEx with no LOCKFILE_FAIL_IMMEDIATELY blocks until acquired
+	ret, _, lockErr := procLockFileEx.Call(
+		f.Fd(),
+		uintptr(lockfileExclusiveLock),
+		0,
+		^uintptr(0),
+		^uintptr(0),
+		uintptr(unsafe.Pointer(overlapped)),
+	)
+	if ret == 0 {
+		_ = f.Close()
+
+		return nil, lockErr
+	}
+
+	return func() {
+		_, _, _ = procUnlockFileEx.Call(
+			f.Fd(),
+			0,
+			^uintptr(0),
+			^uintptr(0),
+			uintptr(unsafe.Pointer(overlapped)),
+		)
+		_ = f.Close()
+	}, nil
+}
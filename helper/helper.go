@@ -1,10 +1,10 @@
 package helper
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"log"
 	"net"
 	"net/http"
 	"os"
@@ -12,6 +12,10 @@ import (
 	"runtime"
 	"strings"
 	"time"
+
+	"github.com/meinside/google-ddns-updater/helper/metrics"
+	"github.com/meinside/google-ddns-updater/helper/notify"
+	"github.com/meinside/google-ddns-updater/helper/providers"
 )
 
 // Configs struct for configurations
@@ -24,18 +28,31 @@ import (
 //	   {
 //	     "hostname": "YOUR-SUBDOMAIN1.DOMAIN.TLD",
 //	     "username": "0123456789abcdefg",
-//	     "password": "abcdefg0123456789"
+//	     "password": "env:DDNS_PASSWORD"
 //	   },
 //	   {
 //	     "hostname": "YOUR-SUBDOMAIN2.DOMAIN.TLD",
 //	     "username": "9876543210abcdefg",
-//	     "password": "abcdefg9876543210"
+//	     "password": "abcdefg9876543210",
+//	     "record_type": "both"
+//	   }
+//	 ],
+//	 "notifications": [
+//	   {
+//	     "type": "slack",
+//	     "url": "https://hooks.slack.com/services/...",
+//	     "events": ["update_failed", "abuse_blocked", "auth_failed"]
 //	   }
 //	 ]
 //	}
 type Configs struct {
 	IPAddress string   `json:"ip,omitempty"`
 	Configs   []Config `json:"configs"`
+
+	// Notifications lists the sinks to notify of ddns lifecycle events
+	// (ip changes, update successes/failures, provider-reported abuse
+	// blocks or auth failures); see BuildNotifier
+	Notifications []NotificationSink `json:"notifications,omitempty"`
 }
 
 // Config struct for each configuration
@@ -43,6 +60,39 @@ type Config struct {
 	Hostname string `json:"hostname"`
 	Username string `json:"username"`
 	Password string `json:"password"`
+
+	// RecordType selects which dns record(s) to keep up to date: "A" (the
+	// default), "AAAA", or "both"
+	RecordType string `json:"record_type,omitempty"`
+
+	// Provider selects the DDNS service to update ("google", the default,
+	// "cloudflare", or "dyndns2")
+	Provider string `json:"provider,omitempty"`
+
+	// APIToken, ZoneID, RecordID, and UpdateURL are opaque, provider-specific
+	// fields used by some Provider implementations (e.g. Cloudflare's
+	// zone_id/record_id/api_token, or a generic DynDNSv2 endpoint's update_url)
+	APIToken  string `json:"api_token,omitempty"`
+	ZoneID    string `json:"zone_id,omitempty"`
+	RecordID  string `json:"record_id,omitempty"`
+	UpdateURL string `json:"update_url,omitempty"`
+}
+
+// defaultProvider is used when Config.Provider is not set, for backward
+// compatibility with configs written before DDNS providers other than
+// Google Domains existed
+const defaultProvider = "google"
+
+// RecordTypes returns the dns record types this config should keep updated
+func (c Config) RecordTypes() []string {
+	switch strings.ToUpper(c.RecordType) {
+	case "AAAA":
+		return []string{"AAAA"}
+	case "BOTH":
+		return []string{"A", "AAAA"}
+	default:
+		return []string{"A"}
+	}
 }
 
 // constants
@@ -53,7 +103,6 @@ const (
 	ipCacheFilename       = "ip.cache"
 
 	checkIPURL      = "https://domains.google.com/checkip"
-	apiURLFormat    = "https://%s:%s@domains.google.com/nic/update?hostname=%s&myip=%s"
 	userAgentFormat = "Google-DDNS-Updater/%s (golang; %s; %s)"
 	fallbackIP      = "0.0.0.0"
 )
@@ -79,170 +128,156 @@ func DefaultConfFilepath() string {
 	return filepath.Join(pwd(), defaultConfigFilename)
 }
 
-// ReadConfigs reads configs file
+// ReadConfigs reads configs file, transparently decrypting it if it was
+// written by `encrypt-config`, and resolving any indirect credential
+// references (env:/file:/keyring:/op://) in place
 func ReadConfigs(filepath string) (result Configs, err error) {
 	var file []byte
-	file, err = os.ReadFile(filepath)
-	if err == nil {
-		if err = json.Unmarshal(file, &result); err == nil {
-			return result, nil
-		}
+	if file, err = os.ReadFile(filepath); err != nil {
+		return Configs{}, err
 	}
 
-	return Configs{}, err
-}
-
-// GetExternalIP gets external IP address of this host
-func GetExternalIP() (string, error) {
-	var err error
-
-	httpClient := defaultHTTPClient()
-
-	// http get request
-	var req *http.Request
-	if req, err = http.NewRequest("GET", checkIPURL, nil); err == nil {
-		// user-agent
-		req.Header.Set("User-Agent", userAgent())
-
-		// http get
-		var resp *http.Response
-		resp, err = httpClient.Do(req)
-
-		if resp != nil {
-			defer resp.Body.Close() // in case of http redirects
+	if strings.HasPrefix(string(file), encryptedConfigPrefix) {
+		if file, err = decryptConfig(file); err != nil {
+			return Configs{}, fmt.Errorf("failed to decrypt configs file: %s", err)
 		}
+	}
 
-		if err == nil && resp.StatusCode == 200 {
-			var body []byte
-			if body, err = io.ReadAll(resp.Body); err == nil {
-				ip := strings.TrimSpace(string(body))
-
-				return ip, nil
-			}
-
-			err = fmt.Errorf("failed to read external ip: %s", err)
-		} else {
-			err = fmt.Errorf("failed to fetch external ip: %s (http %d)", err, resp.StatusCode)
-		}
+	if err = json.Unmarshal(file, &result); err != nil {
+		return Configs{}, err
 	}
 
-	return fallbackIP, err
-}
+	if err = resolveConfigSecrets(&result); err != nil {
+		return Configs{}, err
+	}
 
-// get ip cache file's path
-func ipCacheFilepath(cacheDir, hostname string) string {
-	return filepath.Join(cacheDir, ipCacheFilename+"."+hostname)
+	return result, nil
 }
 
-// LoadCachedIP loads cached ip address for given config
-func LoadCachedIP(conf Config, cacheDir string) (string, error) {
-	var err error
-
-	filepath := ipCacheFilepath(cacheDir, conf.Hostname)
-
-	if _, err = os.Stat(filepath); err != nil && os.IsNotExist(err) {
-		log.Printf("ip cache file: %s does not exist", filepath)
-
-		_ = cacheIP(conf, cacheDir, fallbackIP)
+// GetExternalIP gets external ipv4 address of this host, via the active
+// resolver (SetResolverMode's "chain", by default)
+func GetExternalIP(ctx context.Context) (string, error) {
+	ip, err := activeResolver.ResolveIP(ctx, IPv4)
+	if err != nil {
+		metrics.RecordExternalIPFetchFailure(string(IPv4))
 
-		return fallbackIP, nil
+		return fallbackIP, err
 	}
 
-	var data []byte
-	data, err = os.ReadFile(filepath)
+	return ip, nil
+}
 
-	if err == nil {
-		log.Printf("loaded cached ip: %s from file: %s", string(data), filepath)
+// GetExternalIPv6 gets external ipv6 address of this host, via the active
+// resolver (SetResolverMode's "chain", by default)
+func GetExternalIPv6(ctx context.Context) (string, error) {
+	ip, err := activeResolver.ResolveIP(ctx, IPv6)
+	if err != nil {
+		metrics.RecordExternalIPFetchFailure(string(IPv6))
+
+		return fallbackIP, err
 	}
 
-	return string(data), err
+	return ip, nil
 }
 
-// cache ip locally
-func cacheIP(conf Config, cacheDir, ip string) error {
-	filepath := ipCacheFilepath(cacheDir, conf.Hostname)
+// LoadCachedIP loads the cached ip address of the given family for given
+// config, via the active CacheBackend (file, by default)
+func LoadCachedIP(conf Config, cacheDir string, family IPFamily) (string, error) {
+	return cacheBackend.Load(cacheDir, conf, family)
+}
 
-	log.Printf("caching ip: %s to file: %s", ip, filepath)
+// SyncIP compares wantIP against the cached address for conf's hostname and
+// family and, if it differs, dispatches UpdateIP and records the new cache
+// value — all under one CacheBackend lock acquisition, so two overlapping
+// invocations (cron + manual run, or two daemon ticks) can't both observe
+// the same stale cached ip, both push a duplicate update to the DDNS
+// provider, and race each other's cache writes. changed reports whether
+// wantIP differed from the cache (and was therefore applied).
+func SyncIP(ctx context.Context, conf Config, cacheDir string, family IPFamily, wantIP string) (changed bool, oldIP string, err error) {
+	changed, oldIP, err = cacheBackend.CompareAndUpdate(cacheDir, conf, family, wantIP, func(oldIP string) error {
+		return UpdateIP(ctx, conf, family, oldIP, wantIP)
+	})
+
+	// record a heartbeat on every successful check, not just ones that
+	// actually changed the record, so /metrics stays current through the
+	// common steady-state case of a long-unchanged address
+	if err == nil {
+		metrics.RecordHeartbeat(conf.Hostname, string(family), wantIP)
+	}
 
-	return os.WriteFile(filepath, []byte(ip), 0644)
+	return changed, oldIP, err
 }
 
-// UpdateIP updates ip address for given config
-func UpdateIP(conf Config, cacheDir, ip string) error {
-	var err error
+// UpdateIP updates the dns record of the given family for given config, by
+// dispatching to the Provider named in conf.Provider (defaultProvider, i.e.
+// Google Domains, when unset). oldIP is the previously cached address, used
+// only to annotate fired notification events. Prefer SyncIP over calling
+// this directly: it doesn't touch the ip cache itself, so callers must hold
+// off any concurrent cache read/write for the same hostname/family themselves.
+func UpdateIP(ctx context.Context, conf Config, family IPFamily, oldIP, ip string) error {
+	name := conf.Provider
+	if name == "" {
+		name = defaultProvider
+	}
 
-	httpClient := defaultHTTPClient()
+	started := time.Now()
 
-	// api url
-	apiURL := fmt.Sprintf(apiURLFormat, conf.Username, conf.Password, conf.Hostname, ip)
+	notifier.Fire(ctx, notify.Payload{
+		Event: notify.EventIPChanged, Hostname: conf.Hostname,
+		OldIP: oldIP, NewIP: ip, Provider: name, Time: started,
+	})
 
-	// http post request
-	var req *http.Request
-	if req, err = http.NewRequest("POST", apiURL, nil); err == nil {
-		// user-agent
-		req.Header.Set("User-Agent", userAgent())
+	provider, exists := providers.Get(name)
+	if !exists {
+		err := fmt.Errorf("no such provider registered: %s", name)
 
-		// http post
-		var resp *http.Response
-		resp, err = httpClient.Do(req)
+		metrics.RecordUpdate(conf.Hostname, "failure", time.Since(started))
+		notifier.Fire(ctx, notify.Payload{
+			Event: notify.EventUpdateFailed, Hostname: conf.Hostname,
+			OldIP: oldIP, NewIP: ip, Provider: name, Response: err.Error(), Time: time.Now(),
+		})
 
-		if resp != nil {
-			defer resp.Body.Close()
-		}
+		return err
+	}
 
-		if err == nil {
-			var bytes []byte
-			if bytes, err = io.ReadAll(resp.Body); err == nil {
-				err = checkResponse(conf, cacheDir, string(bytes), ip)
-			}
+	if err := provider.Update(ctx, providers.Target{
+		Hostname:  conf.Hostname,
+		Username:  conf.Username,
+		Password:  conf.Password,
+		APIToken:  conf.APIToken,
+		ZoneID:    conf.ZoneID,
+		RecordID:  conf.RecordID,
+		UpdateURL: conf.UpdateURL,
+	}, ip); err != nil {
+		metrics.RecordUpdate(conf.Hostname, "failure", time.Since(started))
+
+		event := notify.EventUpdateFailed
+		switch {
+		case errors.Is(err, providers.ErrAuthFailed):
+			event = notify.EventAuthFailed
+		case errors.Is(err, providers.ErrAbuseBlocked):
+			event = notify.EventAbuseBlocked
 		}
-	}
 
-	return err
-}
+		notifier.Fire(ctx, notify.Payload{
+			Event: event, Hostname: conf.Hostname,
+			OldIP: oldIP, NewIP: ip, Provider: name, Response: err.Error(), Time: time.Now(),
+		})
 
-// check response from google domains
-func checkResponse(conf Config, cacheDir, response, ip string) error {
-	var err error
+		return err
+	}
 
-	//log.Printf("response from google domains: %s", response)
+	metrics.RecordUpdate(conf.Hostname, "success", time.Since(started))
+	metrics.RecordCurrentIP(conf.Hostname, string(family), ip)
 
-	comps := strings.Split(response, " ")
+	logger.Infof("update was successful for hostname: %s via provider: %s", conf.Hostname, name)
 
-	if len(comps) >= 2 {
-		// success
-		if ip == comps[1] {
-			_ = cacheIP(conf, cacheDir, ip)
-		} else {
-			err = fmt.Errorf("returned ip differs from the requested one: %s and %s", comps[1], ip)
-		}
-		switch comps[0] {
-		case "good":
-			log.Printf("update was successful")
-		case "nochg":
-			log.Printf("ip address: %s is already set for hostname: %s", ip, conf.Hostname)
-		}
-	} else {
-		// errors
-		switch response {
-		case "nohost":
-			err = fmt.Errorf("hostname: %s does not exist, or does not have ddns enabled", conf.Hostname)
-		case "badauth":
-			err = fmt.Errorf("username and password combination: %s / %s is not valid for hostname: %s", conf.Username, conf.Password, conf.Hostname)
-		case "notfqdn":
-			err = fmt.Errorf("supplied hostname: %s is not a valid fully-qualified domain name", conf.Hostname)
-		case "badagent":
-			err = fmt.Errorf("user agent: %s is not valid", userAgent())
-		case "abuse":
-			err = fmt.Errorf("access for the hostname: %s has been blocked due to failure to interpret previous responses correctly", conf.Hostname)
-		case "911":
-			err = fmt.Errorf("internal server error on google")
-		default:
-			err = fmt.Errorf("unhandled response from server: %s", response)
-		}
-	}
+	notifier.Fire(ctx, notify.Payload{
+		Event: notify.EventUpdateSuccess, Hostname: conf.Hostname,
+		OldIP: oldIP, NewIP: ip, Provider: name, Time: time.Now(),
+	})
 
-	return err
+	return nil
 }
 
 // get default http client
@@ -274,7 +309,7 @@ func ConfigForHostname(confs Configs, hostname string) *Config {
 
 // ExitWithError exits program with error message
 func ExitWithError(format string, a ...interface{}) {
-	log.Printf(format, a...)
+	logger.Errorf(format, a...)
 
 	os.Exit(1)
 }
@@ -295,6 +330,31 @@ $ google-ddns-updater subdomain1.domain.com subdomain2.domain.com -c /path/to/co
 
 # update specific domains with certain ip address
 $ google-ddns-updater -i 255.255.255.255 subdomain1.domain.com subdomain2.domain.com -c /path/to/config-file.json
+
+# run as a daemon, re-checking the external ip every 10 minutes (SIGHUP reloads config, SIGTERM/SIGINT shut down)
+$ google-ddns-updater -d --interval 600 -c /path/to/config-file.json
+
+# also serve prometheus metrics on /metrics and a healthcheck on /healthz
+$ google-ddns-updater -d --interval 600 --metrics-addr :9090 -c /path/to/config-file.json
+
+# cache ip addresses somewhere other than the config file's directory, using a single sqlite db with history
+$ google-ddns-updater -d --cache-dir /var/lib/google-ddns-updater --cache-backend sqlite -c /path/to/config-file.json
+
+# resolve the external ip by majority vote across google-checkip/icanhazip/ipify, instead of falling back through them in order
+$ google-ddns-updater --resolver-mode majority -c /path/to/config-file.json
+
+# resolve the external ip via OpenDNS, a fixed static address, or a local network interface instead
+$ google-ddns-updater --resolver-mode opendns -c /path/to/config-file.json
+$ google-ddns-updater --resolver-mode static:203.0.113.5 -c /path/to/config-file.json
+$ google-ddns-updater --resolver-mode interface:eth0 -c /path/to/config-file.json
+
+# emit JSON logs (for log aggregators), quiet logs (warnings/errors only), or forward to syslog instead of plain text
+$ google-ddns-updater --log-format json -c /path/to/config-file.json
+$ google-ddns-updater --log-format quiet -c /path/to/config-file.json
+$ google-ddns-updater --log-format syslog -c /path/to/config-file.json
+
+# encrypt a plaintext config file at rest, using GOOGLE_DDNS_KEY (or a keyring entry) as the passphrase
+$ google-ddns-updater encrypt-config /path/to/config.json /path/to/config.json.enc
 `)
 
 	os.Exit(0)
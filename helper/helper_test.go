@@ -0,0 +1,29 @@
+package helper
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConfigRecordTypes(t *testing.T) {
+	tests := []struct {
+		recordType string
+		want       []string
+	}{
+		{recordType: "", want: []string{"A"}},
+		{recordType: "A", want: []string{"A"}},
+		{recordType: "AAAA", want: []string{"AAAA"}},
+		{recordType: "aaaa", want: []string{"AAAA"}},
+		{recordType: "both", want: []string{"A", "AAAA"}},
+		{recordType: "BOTH", want: []string{"A", "AAAA"}},
+		{recordType: "unrecognized", want: []string{"A"}},
+	}
+
+	for _, tc := range tests {
+		conf := Config{RecordType: tc.recordType}
+
+		if got := conf.RecordTypes(); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("RecordTypes() for record_type: %q = %v, want: %v", tc.recordType, got, tc.want)
+		}
+	}
+}
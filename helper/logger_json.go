@@ -0,0 +1,60 @@
+package helper
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// jsonLogger emits one JSON object per line to stdout, for operators who
+// want to feed this tool's output into a log aggregator instead of parsing free text
+type jsonLogger struct {
+	minLevel LogLevel
+}
+
+// jsonLogLine is the shape of a single emitted log line
+type jsonLogLine struct {
+	Time  string `json:"time"`
+	Level string `json:"level"`
+	Msg   string `json:"msg"`
+}
+
+// NewJSONLogger returns a Logger that writes one JSON object per line to
+// stdout, dropping messages below minLevel
+func NewJSONLogger(minLevel LogLevel) Logger {
+	return &jsonLogger{minLevel: minLevel}
+}
+
+func (l *jsonLogger) Debugf(format string, a ...interface{}) {
+	l.logf(LogLevelDebug, "debug", format, a...)
+}
+
+func (l *jsonLogger) Infof(format string, a ...interface{}) {
+	l.logf(LogLevelInfo, "info", format, a...)
+}
+
+func (l *jsonLogger) Warnf(format string, a ...interface{}) {
+	l.logf(LogLevelWarn, "warn", format, a...)
+}
+
+func (l *jsonLogger) Errorf(format string, a ...interface{}) {
+	l.logf(LogLevelError, "error", format, a...)
+}
+
+func (l *jsonLogger) logf(level LogLevel, levelName, format string, a ...interface{}) {
+	if level < l.minLevel {
+		return
+	}
+
+	line, err := json.Marshal(jsonLogLine{
+		Time:  time.Now().Format(time.RFC3339),
+		Level: levelName,
+		Msg:   fmt.Sprintf(format, a...),
+	})
+	if err != nil {
+		return
+	}
+
+	fmt.Fprintln(os.Stdout, string(line))
+}
@@ -0,0 +1,57 @@
+//go:build !windows
+
+package helper
+
+import (
+	"fmt"
+	"log/syslog"
+)
+
+// syslogLogger forwards messages to the local syslog daemon
+type syslogLogger struct {
+	minLevel LogLevel
+	writer   *syslog.Writer
+}
+
+// NewSyslogLogger returns a Logger that forwards to the local syslog
+// daemon under the "google-ddns-updater" tag, dropping messages below minLevel
+func NewSyslogLogger(minLevel LogLevel) (Logger, error) {
+	writer, err := syslog.New(syslog.LOG_INFO, "google-ddns-updater")
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to syslog: %s", err)
+	}
+
+	return &syslogLogger{minLevel: minLevel, writer: writer}, nil
+}
+
+func (l *syslogLogger) Debugf(format string, a ...interface{}) {
+	if LogLevelDebug < l.minLevel {
+		return
+	}
+
+	_ = l.writer.Debug(fmt.Sprintf(format, a...))
+}
+
+func (l *syslogLogger) Infof(format string, a ...interface{}) {
+	if LogLevelInfo < l.minLevel {
+		return
+	}
+
+	_ = l.writer.Info(fmt.Sprintf(format, a...))
+}
+
+func (l *syslogLogger) Warnf(format string, a ...interface{}) {
+	if LogLevelWarn < l.minLevel {
+		return
+	}
+
+	_ = l.writer.Warning(fmt.Sprintf(format, a...))
+}
+
+func (l *syslogLogger) Errorf(format string, a ...interface{}) {
+	if LogLevelError < l.minLevel {
+		return
+	}
+
+	_ = l.writer.Err(fmt.Sprintf(format, a...))
+}
@@ -0,0 +1,11 @@
+//go:build windows
+
+package helper
+
+import "fmt"
+
+// NewSyslogLogger returns an error on windows: there is no local syslog
+// daemon to forward to (Go's log/syslog is unix-only)
+func NewSyslogLogger(_ LogLevel) (Logger, error) {
+	return nil, fmt.Errorf("syslog logging is not supported on windows")
+}
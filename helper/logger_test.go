@@ -0,0 +1,19 @@
+package helper
+
+import (
+	"testing"
+)
+
+func TestSetLogFormat(t *testing.T) {
+	defer func() { logger = NewStandardLogger(LogLevelInfo) }()
+
+	for _, format := range []string{"", "text", "quiet", "json"} {
+		if err := SetLogFormat(format); err != nil {
+			t.Errorf("SetLogFormat(%q) unexpected error: %s", format, err)
+		}
+	}
+
+	if err := SetLogFormat("unrecognized"); err == nil {
+		t.Error("SetLogFormat(\"unrecognized\") expected an error, got nil")
+	}
+}
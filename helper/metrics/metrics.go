@@ -0,0 +1,181 @@
+// Package metrics is a small, dependency-free Prometheus text-exposition
+// registry, covering the handful of counters and gauges this tool reports
+// on its optional /metrics endpoint.
+package metrics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// labelKey is a canonical, comparable representation of a metric's labels
+type labelKey string
+
+func keyFor(labels map[string]string) labelKey {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&b, "%s=%q,", name, labels[name])
+	}
+
+	return labelKey(b.String())
+}
+
+type sample struct {
+	labels map[string]string
+	value  float64
+}
+
+// metric is a single named counter or gauge, broken down by label set
+type metric struct {
+	name string
+	help string
+	kind string // "counter" or "gauge"
+
+	mu      sync.Mutex
+	samples map[labelKey]*sample
+}
+
+func newMetric(name, help, kind string) *metric {
+	return &metric{name: name, help: help, kind: kind, samples: map[labelKey]*sample{}}
+}
+
+func (m *metric) set(labels map[string]string, value float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := keyFor(labels)
+	if s, exists := m.samples[key]; exists {
+		s.value = value
+	} else {
+		m.samples[key] = &sample{labels: labels, value: value}
+	}
+}
+
+func (m *metric) add(labels map[string]string, delta float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := keyFor(labels)
+	if s, exists := m.samples[key]; exists {
+		s.value += delta
+	} else {
+		m.samples[key] = &sample{labels: labels, value: delta}
+	}
+}
+
+func (m *metric) write(b *strings.Builder) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.samples) == 0 {
+		return
+	}
+
+	fmt.Fprintf(b, "# HELP %s %s\n", m.name, m.help)
+	fmt.Fprintf(b, "# TYPE %s %s\n", m.name, m.kind)
+
+	keys := make([]labelKey, 0, len(m.samples))
+	for key := range m.samples {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+	for _, key := range keys {
+		s := m.samples[key]
+		fmt.Fprintf(b, "%s%s %v\n", m.name, formatLabels(s.labels), s.value)
+	}
+}
+
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s=%q", name, labels[name]))
+	}
+
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+// metrics reported on the /metrics endpoint
+var (
+	updateTotal = newMetric("ddns_update_total",
+		"Total number of ddns update attempts, by hostname and result.", "counter")
+	updateDurationSeconds = newMetric("ddns_update_duration_seconds",
+		"Duration of the most recent ddns update, by hostname.", "gauge")
+	externalIPFetchFailuresTotal = newMetric("ddns_external_ip_fetch_failures_total",
+		"Total number of failed external ip fetches, by family.", "counter")
+	currentIP = newMetric("ddns_current_ip",
+		"Always 1; the currently-cached ip is reported as the \"ip\" label, by hostname and family.", "gauge")
+	lastSuccessTimestampSeconds = newMetric("ddns_last_success_timestamp_seconds",
+		"Unix timestamp of the last successful update or confirmed-in-sync check, by hostname.", "gauge")
+)
+
+// RecordUpdate records the outcome ("success" or "failure") and duration of
+// a ddns update attempt for hostname
+func RecordUpdate(hostname, result string, duration time.Duration) {
+	updateTotal.add(map[string]string{"hostname": hostname, "result": result}, 1)
+	updateDurationSeconds.set(map[string]string{"hostname": hostname}, duration.Seconds())
+
+	if result == "success" {
+		lastSuccessTimestampSeconds.set(map[string]string{"hostname": hostname}, float64(time.Now().Unix()))
+	}
+}
+
+// RecordExternalIPFetchFailure records a failed external ip fetch for the given family ("v4" or "v6")
+func RecordExternalIPFetchFailure(family string) {
+	externalIPFetchFailuresTotal.add(map[string]string{"family": family}, 1)
+}
+
+// RecordCurrentIP records the ip currently believed to be set for hostname/family
+func RecordCurrentIP(hostname, family, ip string) {
+	currentIP.set(map[string]string{"hostname": hostname, "family": family, "ip": ip}, 1)
+}
+
+// RecordHeartbeat records that hostname/family was just confirmed to already
+// be in sync at ip: unlike RecordUpdate, this fires on every successful
+// check, not just on ones that actually changed the record, so the current
+// ip and last-success gauges stay fresh (and present after a process
+// restart) through the common steady-state case of a long-unchanged address
+func RecordHeartbeat(hostname, family, ip string) {
+	currentIP.set(map[string]string{"hostname": hostname, "family": family, "ip": ip}, 1)
+	lastSuccessTimestampSeconds.set(map[string]string{"hostname": hostname}, float64(time.Now().Unix()))
+}
+
+// Render returns every metric in Prometheus text exposition format
+func Render() string {
+	var b strings.Builder
+
+	for _, m := range []*metric{
+		updateTotal,
+		updateDurationSeconds,
+		externalIPFetchFailuresTotal,
+		currentIP,
+		lastSuccessTimestampSeconds,
+	} {
+		m.write(&b)
+	}
+
+	return b.String()
+}
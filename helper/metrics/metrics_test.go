@@ -0,0 +1,53 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderOmitsUnusedMetrics(t *testing.T) {
+	out := Render()
+
+	if strings.Contains(out, "ddns_update_total") {
+		t.Fatalf("Render() should omit metrics with no recorded samples before any Record* call, got:\n%s", out)
+	}
+}
+
+func TestRender(t *testing.T) {
+	RecordUpdate("sub.example.com", "success", 250*time.Millisecond)
+	RecordExternalIPFetchFailure("v6")
+	RecordCurrentIP("sub.example.com", "v4", "1.2.3.4")
+
+	out := Render()
+
+	for _, want := range []string{
+		"# HELP ddns_update_total ",
+		"# TYPE ddns_update_total counter",
+		`ddns_update_total{hostname="sub.example.com",result="success"} 1`,
+		"# TYPE ddns_update_duration_seconds gauge",
+		`ddns_update_duration_seconds{hostname="sub.example.com"} 0.25`,
+		`ddns_external_ip_fetch_failures_total{family="v6"} 1`,
+		`ddns_current_ip{family="v4",hostname="sub.example.com",ip="1.2.3.4"} 1`,
+		"ddns_last_success_timestamp_seconds",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Render() missing expected substring: %q\ngot:\n%s", want, out)
+		}
+	}
+}
+
+func TestRecordHeartbeat(t *testing.T) {
+	RecordHeartbeat("unchanged.example.com", "v4", "5.6.7.8")
+
+	out := Render()
+
+	for _, want := range []string{
+		`ddns_current_ip{family="v4",hostname="unchanged.example.com",ip="5.6.7.8"} 1`,
+		"ddns_last_success_timestamp_seconds",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Render() missing expected substring after RecordHeartbeat: %q\ngot:\n%s", want, out)
+		}
+	}
+}
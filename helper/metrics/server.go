@@ -0,0 +1,34 @@
+package metrics
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+)
+
+// StartServer starts an http server on addr exposing `/metrics` (Prometheus
+// text format) and `/healthz`, and returns immediately. Call Shutdown/Close
+// on the returned server as part of a graceful shutdown.
+func StartServer(addr string) *http.Server {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		fmt.Fprint(w, Render())
+	})
+
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, "ok")
+	})
+
+	server := &http.Server{Addr: addr, Handler: mux}
+
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Printf("metrics server on %s stopped: %s", addr, err)
+		}
+	}()
+
+	return server
+}
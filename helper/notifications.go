@@ -0,0 +1,114 @@
+package helper
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/meinside/google-ddns-updater/helper/notify"
+)
+
+// NotificationSink configures a single destination (webhook, slack, discord,
+// telegram, or shell command) to be notified of ddns lifecycle events
+type NotificationSink struct {
+	Type string `json:"type"`
+
+	// Enabled defaults to true; set explicitly to false to disable a sink
+	// without removing it from the config
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// Events filters which event types this sink fires for; empty means every event
+	Events []string `json:"events,omitempty"`
+
+	// URL is used by the webhook, slack, and discord sinks
+	URL string `json:"url,omitempty"`
+
+	// BodyTemplate is a Go text/template for the webhook sink's request body
+	BodyTemplate string `json:"body_template,omitempty"`
+
+	// BotToken and ChatID are used by the telegram sink
+	BotToken string `json:"bot_token,omitempty"`
+	ChatID   string `json:"chat_id,omitempty"`
+
+	// Command is used by the shell sink
+	Command string `json:"command,omitempty"`
+}
+
+// defaultNotifyRetries / defaultNotifyBackoff govern the retry-with-backoff
+// wrapper applied to every configured notification sink
+const (
+	defaultNotifyRetries = 3
+	defaultNotifyBackoff = 2 * time.Second
+)
+
+// defaultNotifyDrainTimeout bounds how long WaitForNotifications blocks,
+// comfortably above the worst case of defaultNotifyRetries attempts at
+// defaultNotifyBackoff (doubling) plus delivery time
+const defaultNotifyDrainTimeout = 30 * time.Second
+
+// notifier is the package-level Notifier used by UpdateIP; override it with SetNotifier
+var notifier = notify.NewNotifier()
+
+// SetNotifier replaces the package-level notifier, e.g. with one built by BuildNotifier
+func SetNotifier(n *notify.Notifier) {
+	if n != nil {
+		notifier = n
+	}
+}
+
+// WaitForNotifications blocks until every notification fired so far
+// (including retries) has finished, or defaultNotifyDrainTimeout elapses.
+// Callers about to exit the process — e.g. main()'s one-shot/cron path —
+// must call this, or sinks fired via Notifier.Fire's background goroutines
+// are silently dropped when the process exits out from under them.
+func WaitForNotifications() {
+	notifier.Wait(defaultNotifyDrainTimeout)
+}
+
+// BuildNotifier builds a Notifier from the `notifications` section of Configs
+func BuildNotifier(confs Configs) (*notify.Notifier, error) {
+	sinks := make([]notify.Sink, 0, len(confs.Notifications))
+
+	for _, sinkConf := range confs.Notifications {
+		if sinkConf.Enabled != nil && !*sinkConf.Enabled {
+			continue
+		}
+
+		sink, err := newSink(sinkConf)
+		if err != nil {
+			return nil, err
+		}
+
+		sink = notify.WithRetry(sink, defaultNotifyRetries, defaultNotifyBackoff)
+
+		if len(sinkConf.Events) > 0 {
+			events := make([]notify.Event, 0, len(sinkConf.Events))
+			for _, e := range sinkConf.Events {
+				events = append(events, notify.Event(e))
+			}
+
+			sink = notify.Filter(sink, events...)
+		}
+
+		sinks = append(sinks, sink)
+	}
+
+	return notify.NewNotifier(sinks...), nil
+}
+
+// newSink builds the concrete Sink for a single NotificationSink entry
+func newSink(conf NotificationSink) (notify.Sink, error) {
+	switch conf.Type {
+	case "webhook":
+		return notify.NewWebhookSink(conf.URL, conf.BodyTemplate)
+	case "slack":
+		return notify.NewSlackSink(conf.URL), nil
+	case "discord":
+		return notify.NewDiscordSink(conf.URL), nil
+	case "telegram":
+		return notify.NewTelegramSink(conf.BotToken, conf.ChatID), nil
+	case "shell":
+		return notify.NewShellSink(conf.Command), nil
+	default:
+		return nil, fmt.Errorf("unknown notification sink type: %s", conf.Type)
+	}
+}
@@ -0,0 +1,25 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// discordSink posts to a Discord webhook
+type discordSink struct {
+	url string
+}
+
+// NewDiscordSink returns a Sink that posts to a Discord webhook url
+func NewDiscordSink(url string) Sink {
+	return &discordSink{url: url}
+}
+
+func (s *discordSink) Notify(ctx context.Context, payload Payload) error {
+	body, err := json.Marshal(map[string]string{"content": formatMessage(payload)})
+	if err != nil {
+		return err
+	}
+
+	return postJSON(ctx, s.url, body)
+}
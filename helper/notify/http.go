@@ -0,0 +1,55 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// postJSON posts body to url with an appropriate content-type, shared by
+// every http-backed sink in this package
+func postJSON(ctx context.Context, url string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	resp, err := client.Do(req)
+	if resp != nil {
+		defer resp.Body.Close()
+	}
+	if err != nil {
+		return fmt.Errorf("notification delivery failed: %s", err)
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("notification delivery failed: http %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// formatMessage renders a Payload as a short, human-readable line for
+// chat-oriented sinks (Slack, Discord, Telegram)
+func formatMessage(payload Payload) string {
+	msg := fmt.Sprintf("[%s] %s: %s -> %s (provider: %s)",
+		payload.Event, payload.Hostname, orDash(payload.OldIP), orDash(payload.NewIP), payload.Provider)
+
+	if payload.Response != "" {
+		msg += ": " + payload.Response
+	}
+
+	return msg
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+
+	return s
+}
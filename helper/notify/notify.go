@@ -0,0 +1,149 @@
+// Package notify delivers ddns lifecycle events (ip changes, update
+// successes/failures, provider-reported abuse blocks or auth failures) to
+// configurable sinks: webhooks, chat apps, or a local shell command.
+package notify
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Event identifies the kind of ddns lifecycle event a Sink can be notified about
+type Event string
+
+// events a Sink may be notified about
+const (
+	EventIPChanged     Event = "ip_changed"
+	EventUpdateSuccess Event = "update_success"
+	EventUpdateFailed  Event = "update_failed"
+	EventAbuseBlocked  Event = "abuse_blocked"
+	EventAuthFailed    Event = "auth_failed"
+)
+
+// Payload carries everything a Sink needs to describe what happened
+type Payload struct {
+	Event    Event
+	Hostname string
+	OldIP    string
+	NewIP    string
+	Provider string
+	Response string
+	Time     time.Time
+}
+
+// Sink delivers a Payload somewhere: a webhook, a chat app, a shell command, etc.
+type Sink interface {
+	Notify(ctx context.Context, payload Payload) error
+}
+
+// Notifier fans a Payload out to every one of its Sinks
+type Notifier struct {
+	sinks []Sink
+	wg    sync.WaitGroup
+}
+
+// NewNotifier returns a Notifier that fires every one of `sinks` on every event
+func NewNotifier(sinks ...Sink) *Notifier {
+	return &Notifier{sinks: sinks}
+}
+
+// Fire delivers payload to every configured sink concurrently. Delivery
+// failures are logged, not returned, since a notification should never
+// block or fail the ddns update it's reporting on. Each in-flight delivery
+// is tracked so Wait can block callers that are about to exit until it's
+// done (or retried, for a retry-wrapped sink).
+func (n *Notifier) Fire(ctx context.Context, payload Payload) {
+	for _, sink := range n.sinks {
+		n.wg.Add(1)
+
+		go func(s Sink) {
+			defer n.wg.Done()
+
+			if err := s.Notify(ctx, payload); err != nil {
+				log.Printf("notification delivery failed for event: %s: %s", payload.Event, err)
+			}
+		}(sink)
+	}
+}
+
+// Wait blocks until every in-flight Fire call's sinks have finished (including
+// retry backoff), or timeout elapses, whichever comes first. Short-lived
+// one-shot/cron invocations should call this before exiting, since the
+// process otherwise exits out from under the goroutines Fire spawned and
+// silently drops any notification (and its retries) not yet delivered.
+func (n *Notifier) Wait(timeout time.Duration) {
+	done := make(chan struct{})
+
+	go func() {
+		n.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		log.Printf("timed out after %s waiting for notifications to finish", timeout)
+	}
+}
+
+// filteredSink only forwards payloads whose Event is in its allow-list
+type filteredSink struct {
+	sink   Sink
+	events map[Event]bool
+}
+
+// Filter wraps sink so it only fires for the given events
+func Filter(sink Sink, events ...Event) Sink {
+	allowed := make(map[Event]bool, len(events))
+	for _, e := range events {
+		allowed[e] = true
+	}
+
+	return &filteredSink{sink: sink, events: allowed}
+}
+
+func (f *filteredSink) Notify(ctx context.Context, payload Payload) error {
+	if len(f.events) > 0 && !f.events[payload.Event] {
+		return nil
+	}
+
+	return f.sink.Notify(ctx, payload)
+}
+
+// retryingSink retries a failed delivery with exponential backoff
+type retryingSink struct {
+	sink        Sink
+	maxAttempts int
+	baseDelay   time.Duration
+}
+
+// WithRetry wraps sink so failed deliveries are retried up to maxAttempts
+// times, doubling baseDelay after each failure
+func WithRetry(sink Sink, maxAttempts int, baseDelay time.Duration) Sink {
+	return &retryingSink{sink: sink, maxAttempts: maxAttempts, baseDelay: baseDelay}
+}
+
+func (r *retryingSink) Notify(ctx context.Context, payload Payload) error {
+	var err error
+
+	delay := r.baseDelay
+	for attempt := 0; attempt < r.maxAttempts; attempt++ {
+		if err = r.sink.Notify(ctx, payload); err == nil {
+			return nil
+		}
+
+		if attempt < r.maxAttempts-1 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(delay):
+			}
+
+			delay *= 2
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,38 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// shellSink runs a local shell command, passing the event through the environment
+type shellSink struct {
+	command string
+}
+
+// NewShellSink returns a Sink that runs command via `sh -c`, with the event
+// exposed as DDNS_EVENT, DDNS_HOSTNAME, DDNS_OLD_IP, DDNS_NEW_IP,
+// DDNS_PROVIDER, and DDNS_RESPONSE environment variables
+func NewShellSink(command string) Sink {
+	return &shellSink{command: command}
+}
+
+func (s *shellSink) Notify(ctx context.Context, payload Payload) error {
+	cmd := exec.CommandContext(ctx, "sh", "-c", s.command)
+	cmd.Env = append(os.Environ(),
+		"DDNS_EVENT="+string(payload.Event),
+		"DDNS_HOSTNAME="+payload.Hostname,
+		"DDNS_OLD_IP="+payload.OldIP,
+		"DDNS_NEW_IP="+payload.NewIP,
+		"DDNS_PROVIDER="+payload.Provider,
+		"DDNS_RESPONSE="+payload.Response,
+	)
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("shell notification command failed: %s (%s)", err, string(output))
+	}
+
+	return nil
+}
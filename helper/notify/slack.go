@@ -0,0 +1,25 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// slackSink posts to a Slack incoming webhook
+type slackSink struct {
+	url string
+}
+
+// NewSlackSink returns a Sink that posts to a Slack incoming webhook url
+func NewSlackSink(url string) Sink {
+	return &slackSink{url: url}
+}
+
+func (s *slackSink) Notify(ctx context.Context, payload Payload) error {
+	body, err := json.Marshal(map[string]string{"text": formatMessage(payload)})
+	if err != nil {
+		return err
+	}
+
+	return postJSON(ctx, s.url, body)
+}
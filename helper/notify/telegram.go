@@ -0,0 +1,33 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// telegramAPIURLFormat is the Telegram Bot API's sendMessage endpoint
+const telegramAPIURLFormat = "https://api.telegram.org/bot%s/sendMessage"
+
+// telegramSink sends a message through a Telegram bot
+type telegramSink struct {
+	botToken string
+	chatID   string
+}
+
+// NewTelegramSink returns a Sink that sends a message via a Telegram bot
+func NewTelegramSink(botToken, chatID string) Sink {
+	return &telegramSink{botToken: botToken, chatID: chatID}
+}
+
+func (s *telegramSink) Notify(ctx context.Context, payload Payload) error {
+	body, err := json.Marshal(map[string]string{
+		"chat_id": s.chatID,
+		"text":    formatMessage(payload),
+	})
+	if err != nil {
+		return err
+	}
+
+	return postJSON(ctx, fmt.Sprintf(telegramAPIURLFormat, s.botToken), body)
+}
@@ -0,0 +1,73 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"text/template"
+	"time"
+)
+
+// webhookSink posts a JSON body to an arbitrary http endpoint: either the
+// default body (built via json.Marshal, so every field is properly
+// escaped), or one rendered from a user-supplied Go text/template when
+// bodyTemplate is non-empty — in which case escaping is the template
+// author's responsibility, same as any other text/template usage.
+type webhookSink struct {
+	url      string
+	template *template.Template // nil selects the default, properly-escaped body
+}
+
+// webhookDefaultBody is the shape of the default (non-templated) webhook body
+type webhookDefaultBody struct {
+	Event    string `json:"event"`
+	Hostname string `json:"hostname"`
+	OldIP    string `json:"old_ip"`
+	NewIP    string `json:"new_ip"`
+	Provider string `json:"provider"`
+	Response string `json:"response"`
+	Time     string `json:"time"`
+}
+
+// NewWebhookSink returns a Sink that POSTs a JSON body to url: bodyTemplate
+// rendered (a Go text/template executed against Payload), or a sensible
+// default when bodyTemplate is empty.
+func NewWebhookSink(url, bodyTemplate string) (Sink, error) {
+	if bodyTemplate == "" {
+		return &webhookSink{url: url}, nil
+	}
+
+	tmpl, err := template.New("webhook").Parse(bodyTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook body_template: %s", err)
+	}
+
+	return &webhookSink{url: url, template: tmpl}, nil
+}
+
+func (s *webhookSink) Notify(ctx context.Context, payload Payload) error {
+	if s.template == nil {
+		body, err := json.Marshal(webhookDefaultBody{
+			Event:    string(payload.Event),
+			Hostname: payload.Hostname,
+			OldIP:    payload.OldIP,
+			NewIP:    payload.NewIP,
+			Provider: payload.Provider,
+			Response: payload.Response,
+			Time:     payload.Time.Format(time.RFC3339),
+		})
+		if err != nil {
+			return err
+		}
+
+		return postJSON(ctx, s.url, body)
+	}
+
+	var body bytes.Buffer
+	if err := s.template.Execute(&body, payload); err != nil {
+		return err
+	}
+
+	return postJSON(ctx, s.url, body.Bytes())
+}
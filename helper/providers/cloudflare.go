@@ -0,0 +1,97 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+)
+
+// cloudflareAPIURLFormat is Cloudflare API v4's dns record endpoint
+const cloudflareAPIURLFormat = "https://api.cloudflare.com/client/v4/zones/%s/dns_records/%s"
+
+// cloudflareProvider updates a dns record through Cloudflare's API v4
+type cloudflareProvider struct{}
+
+// NewCloudflareProvider returns a Provider that updates Cloudflare dns
+// records via `PATCH /zones/{zone_id}/dns_records/{record_id}`, authenticated
+// with a bearer token
+func NewCloudflareProvider() Provider {
+	return &cloudflareProvider{}
+}
+
+type cloudflareUpdateRequest struct {
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Content string `json:"content"`
+}
+
+type cloudflareResponse struct {
+	Success bool `json:"success"`
+	Errors  []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+func (p *cloudflareProvider) Update(ctx context.Context, target Target, ip string) error {
+	if target.ZoneID == "" || target.RecordID == "" || target.APIToken == "" {
+		return fmt.Errorf("cloudflare provider requires zone_id, record_id, and api_token for hostname: %s", target.Hostname)
+	}
+
+	recordType := "A"
+	if parsed := net.ParseIP(ip); parsed != nil && parsed.To4() == nil {
+		recordType = "AAAA"
+	}
+
+	body, err := json.Marshal(cloudflareUpdateRequest{
+		Type:    recordType,
+		Name:    target.Hostname,
+		Content: ip,
+	})
+	if err != nil {
+		return err
+	}
+
+	apiURL := fmt.Sprintf(cloudflareAPIURLFormat, target.ZoneID, target.RecordID)
+
+	var req *http.Request
+	if req, err = http.NewRequestWithContext(ctx, "PATCH", apiURL, bytes.NewReader(body)); err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+target.APIToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	httpClient := providerHTTPClient()
+
+	var resp *http.Response
+	resp, err = httpClient.Do(req)
+	if resp != nil {
+		defer resp.Body.Close()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update cloudflare dns record for hostname: %s: %s", target.Hostname, err)
+	}
+
+	var respBody []byte
+	if respBody, err = io.ReadAll(resp.Body); err != nil {
+		return err
+	}
+
+	var result cloudflareResponse
+	if err = json.Unmarshal(respBody, &result); err != nil {
+		return fmt.Errorf("failed to parse cloudflare response for hostname: %s: %s", target.Hostname, err)
+	}
+
+	if !result.Success {
+		if len(result.Errors) > 0 {
+			return fmt.Errorf("cloudflare update failed for hostname: %s: %s", target.Hostname, result.Errors[0].Message)
+		}
+
+		return fmt.Errorf("cloudflare update failed for hostname: %s", target.Hostname)
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// dynDNSv2Provider implements the generic DynDNSv2 update protocol shared by
+// Namecheap, Dynu, No-IP, Hurricane Electric, and others: a GET request
+// with hostname/myip query parameters, returning a plaintext "good <ip>" /
+// "nochg <ip>" response
+type dynDNSv2Provider struct{}
+
+// NewDynDNSv2Provider returns a Provider implementing the generic DynDNSv2
+// update protocol. Target.UpdateURL selects the provider's own endpoint
+// (e.g. "https://dynamicdns.park-your-domain.com/update" for Namecheap,
+// "https://api.dynu.com/nic/update" for Dynu)
+func NewDynDNSv2Provider() Provider {
+	return &dynDNSv2Provider{}
+}
+
+func (p *dynDNSv2Provider) Update(ctx context.Context, target Target, ip string) error {
+	if target.UpdateURL == "" {
+		return fmt.Errorf("dyndns2 provider requires update_url for hostname: %s", target.Hostname)
+	}
+
+	query := url.Values{}
+	query.Set("hostname", target.Hostname)
+	query.Set("myip", ip)
+
+	requestURL := target.UpdateURL + "?" + query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", requestURL, nil)
+	if err != nil {
+		return err
+	}
+
+	if target.APIToken != "" {
+		req.Header.Set("Authorization", "Bearer "+target.APIToken)
+	} else if target.Username != "" {
+		req.SetBasicAuth(target.Username, target.Password)
+	}
+
+	httpClient := providerHTTPClient()
+
+	resp, err := httpClient.Do(req)
+	if resp != nil {
+		defer resp.Body.Close()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update dns record for hostname: %s: %s", target.Hostname, err)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	return checkDynDNSv2Response(target, strings.TrimSpace(string(body)))
+}
+
+// checkDynDNSv2Response interprets the generic DynDNSv2 protocol's plaintext
+// update response ("good <ip>" / "nochg <ip>" on success), classifying
+// "badauth"/"abuse" the same way checkGoogleResponse does, since DynDNSv2
+// providers (Namecheap, Dynu, No-IP, Hurricane Electric) share Google's response codes
+func checkDynDNSv2Response(target Target, response string) error {
+	comps := strings.Split(response, " ")
+
+	switch comps[0] {
+	case "good", "nochg":
+		return nil
+	case "badauth":
+		return fmt.Errorf("%w: username and password combination is not valid for hostname: %s", ErrAuthFailed, target.Hostname)
+	case "abuse":
+		return fmt.Errorf("%w: access for the hostname: %s has been blocked due to failure to interpret previous responses correctly", ErrAbuseBlocked, target.Hostname)
+	default:
+		return fmt.Errorf("dyndns2 update failed for hostname: %s: %s", target.Hostname, response)
+	}
+}
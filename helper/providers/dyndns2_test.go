@@ -0,0 +1,44 @@
+package providers
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckDynDNSv2Response(t *testing.T) {
+	target := Target{Hostname: "sub.example.com"}
+
+	tests := []struct {
+		name     string
+		response string
+		wantErr  error // checked with errors.Is when non-nil
+		wantOK   bool
+	}{
+		{name: "good", response: "good 1.2.3.4", wantOK: true},
+		{name: "nochg", response: "nochg 1.2.3.4", wantOK: true},
+		{name: "badauth", response: "badauth", wantErr: ErrAuthFailed},
+		{name: "abuse", response: "abuse", wantErr: ErrAbuseBlocked},
+		{name: "unrecognized", response: "some other message"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkDynDNSv2Response(target, tc.response)
+
+			if tc.wantOK {
+				if err != nil {
+					t.Fatalf("expected no error for response: %q, got: %s", tc.response, err)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatalf("expected an error for response: %q, got nil", tc.response)
+			}
+
+			if tc.wantErr != nil && !errors.Is(err, tc.wantErr) {
+				t.Fatalf("expected error wrapping: %v, got: %s", tc.wantErr, err)
+			}
+		})
+	}
+}
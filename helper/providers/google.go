@@ -0,0 +1,88 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"runtime"
+	"strings"
+)
+
+// constants for the (now-retired) Google Domains DDNS api
+const (
+	googleVersion         = "0.0.5"
+	googleAPIURLFormat    = "https://%s:%s@domains.google.com/nic/update?hostname=%s&myip=%s"
+	googleUserAgentFormat = "Google-DDNS-Updater/%s (golang; %s; %s)"
+)
+
+// googleProvider updates Google Domains' DDNS records
+type googleProvider struct{}
+
+// NewGoogleProvider returns a Provider that updates Google Domains DDNS records
+func NewGoogleProvider() Provider {
+	return &googleProvider{}
+}
+
+func (p *googleProvider) Update(ctx context.Context, target Target, ip string) error {
+	var err error
+
+	httpClient := providerHTTPClient()
+
+	apiURL := fmt.Sprintf(googleAPIURLFormat, target.Username, target.Password, target.Hostname, ip)
+
+	var req *http.Request
+	if req, err = http.NewRequestWithContext(ctx, "POST", apiURL, nil); err == nil {
+		req.Header.Set("User-Agent", googleUserAgent())
+
+		var resp *http.Response
+		resp, err = httpClient.Do(req)
+
+		if resp != nil {
+			defer resp.Body.Close()
+		}
+
+		if err == nil {
+			var body []byte
+			if body, err = io.ReadAll(resp.Body); err == nil {
+				err = checkGoogleResponse(target, string(body), ip)
+			}
+		}
+	}
+
+	return err
+}
+
+func googleUserAgent() string {
+	return fmt.Sprintf(googleUserAgentFormat, googleVersion, runtime.GOOS, runtime.GOARCH)
+}
+
+// checkGoogleResponse interprets Google Domains' plaintext update response
+func checkGoogleResponse(target Target, response, ip string) error {
+	comps := strings.Split(response, " ")
+
+	if len(comps) >= 2 {
+		if ip != comps[1] {
+			return fmt.Errorf("returned ip differs from the requested one: %s and %s", comps[1], ip)
+		}
+
+		return nil
+	}
+
+	switch response {
+	case "nohost":
+		return fmt.Errorf("hostname: %s does not exist, or does not have ddns enabled", target.Hostname)
+	case "badauth":
+		return fmt.Errorf("%w: username and password combination is not valid for hostname: %s", ErrAuthFailed, target.Hostname)
+	case "notfqdn":
+		return fmt.Errorf("supplied hostname: %s is not a valid fully-qualified domain name", target.Hostname)
+	case "badagent":
+		return fmt.Errorf("user agent: %s is not valid", googleUserAgent())
+	case "abuse":
+		return fmt.Errorf("%w: access for the hostname: %s has been blocked due to failure to interpret previous responses correctly", ErrAbuseBlocked, target.Hostname)
+	case "911":
+		return fmt.Errorf("internal server error on google")
+	default:
+		return fmt.Errorf("unhandled response from server: %s", response)
+	}
+}
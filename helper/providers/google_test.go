@@ -0,0 +1,50 @@
+package providers
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckGoogleResponse(t *testing.T) {
+	target := Target{Hostname: "sub.example.com"}
+
+	tests := []struct {
+		name     string
+		response string
+		ip       string
+		wantErr  error // checked with errors.Is when non-nil
+		wantOK   bool
+	}{
+		{name: "good", response: "good 1.2.3.4", ip: "1.2.3.4", wantOK: true},
+		{name: "nochg", response: "nochg 1.2.3.4", ip: "1.2.3.4", wantOK: true},
+		{name: "ip mismatch", response: "good 9.9.9.9", ip: "1.2.3.4"},
+		{name: "nohost", response: "nohost"},
+		{name: "badauth", response: "badauth", wantErr: ErrAuthFailed},
+		{name: "notfqdn", response: "notfqdn"},
+		{name: "badagent", response: "badagent"},
+		{name: "abuse", response: "abuse", wantErr: ErrAbuseBlocked},
+		{name: "911", response: "911"},
+		{name: "unrecognized", response: "whatever this is"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkGoogleResponse(target, tc.response, tc.ip)
+
+			if tc.wantOK {
+				if err != nil {
+					t.Fatalf("expected no error for response: %q, got: %s", tc.response, err)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatalf("expected an error for response: %q, got nil", tc.response)
+			}
+
+			if tc.wantErr != nil && !errors.Is(err, tc.wantErr) {
+				t.Fatalf("expected error wrapping: %v, got: %s", tc.wantErr, err)
+			}
+		})
+	}
+}
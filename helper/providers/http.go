@@ -0,0 +1,24 @@
+package providers
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+// providerHTTPClient returns an http client tuned with the same conservative
+// timeouts used throughout this tool, for providers to share
+func providerHTTPClient() *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			Dial: (&net.Dialer{
+				Timeout:   5 * time.Second,
+				KeepAlive: 30 * time.Second,
+			}).Dial,
+			IdleConnTimeout:       30 * time.Second,
+			TLSHandshakeTimeout:   5 * time.Second,
+			ResponseHeaderTimeout: 5 * time.Second,
+			ExpectContinueTimeout: 1 * time.Second,
+		},
+	}
+}
@@ -0,0 +1,61 @@
+// Package providers abstracts over the various DDNS services this tool can
+// push ip updates to, now that Google Domains' own DDNS is being retired.
+package providers
+
+import (
+	"context"
+	"errors"
+)
+
+// sentinel errors a Provider can wrap (with fmt.Errorf's %w) so that callers
+// can tell provider-reported abuse blocks and auth failures apart from
+// other update errors, regardless of which provider raised them
+var (
+	ErrAuthFailed   = errors.New("authentication failed")
+	ErrAbuseBlocked = errors.New("blocked due to abuse")
+)
+
+// Target describes the hostname and credentials a Provider needs to update
+// a dns record. Fields a particular provider doesn't need are left empty.
+type Target struct {
+	Hostname string
+	Username string
+	Password string
+
+	// APIToken, ZoneID, and RecordID are opaque, provider-specific fields
+	// (e.g. Cloudflare's zone/record ids and bearer token)
+	APIToken string
+	ZoneID   string
+	RecordID string
+
+	// UpdateURL is the provider's update endpoint, for providers (like the
+	// generic DynDNSv2 protocol) whose endpoint varies by host
+	UpdateURL string
+}
+
+// Provider updates a dns record at some DDNS service to point at a new ip address
+type Provider interface {
+	Update(ctx context.Context, target Target, ip string) error
+}
+
+// registry of providers, keyed by the name configured in Config.Provider
+var registry = map[string]Provider{}
+
+// Register adds a Provider under `name` to the registry, so it can be
+// selected via Config.Provider
+func Register(name string, provider Provider) {
+	registry[name] = provider
+}
+
+// Get looks up a registered Provider by name
+func Get(name string) (Provider, bool) {
+	provider, exists := registry[name]
+
+	return provider, exists
+}
+
+func init() {
+	Register("google", NewGoogleProvider())
+	Register("cloudflare", NewCloudflareProvider())
+	Register("dyndns2", NewDynDNSv2Provider())
+}
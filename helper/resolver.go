@@ -0,0 +1,345 @@
+package helper
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// IPFamily identifies an ip address family to resolve
+type IPFamily string
+
+// ip address families
+const (
+	IPv4 IPFamily = "v4"
+	IPv6 IPFamily = "v6"
+)
+
+// IPResolver resolves this host's external ip address for a given family
+type IPResolver interface {
+	// Name returns a short, human-readable identifier for logging
+	Name() string
+
+	// ResolveIP returns this host's external ip address of the given family
+	ResolveIP(ctx context.Context, family IPFamily) (string, error)
+}
+
+// httpIPResolver fetches a plaintext ip address from an http(s) endpoint,
+// with a separate url per ip family
+type httpIPResolver struct {
+	name string
+	urls map[IPFamily]string
+}
+
+func newHTTPIPResolver(name, v4URL, v6URL string) IPResolver {
+	return &httpIPResolver{
+		name: name,
+		urls: map[IPFamily]string{
+			IPv4: v4URL,
+			IPv6: v6URL,
+		},
+	}
+}
+
+func (r *httpIPResolver) Name() string {
+	return r.name
+}
+
+func (r *httpIPResolver) ResolveIP(ctx context.Context, family IPFamily) (string, error) {
+	url := r.urls[family]
+	if url == "" {
+		return "", fmt.Errorf("%s does not support ip family: %s", r.name, family)
+	}
+
+	httpClient := defaultHTTPClient()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", userAgent())
+
+	resp, err := httpClient.Do(req)
+	if resp != nil {
+		defer resp.Body.Close()
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch external ip from %s: %s", r.name, err)
+	}
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("failed to fetch external ip from %s: http %d", r.name, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read external ip from %s: %s", r.name, err)
+	}
+
+	return strings.TrimSpace(string(body)), nil
+}
+
+// GoogleCheckIPResolver resolves this host's external ip via Google's
+// own `checkip` endpoint (ipv4 only)
+func GoogleCheckIPResolver() IPResolver {
+	return newHTTPIPResolver("google-checkip", checkIPURL, "")
+}
+
+// IpifyResolver resolves this host's external ip via ipify.org
+func IpifyResolver() IPResolver {
+	return newHTTPIPResolver("ipify", "https://api.ipify.org", "https://api6.ipify.org")
+}
+
+// IcanhazipResolver resolves this host's external ip via icanhazip.com
+func IcanhazipResolver() IPResolver {
+	return newHTTPIPResolver("icanhazip", "https://icanhazip.com", "https://ipv6.icanhazip.com")
+}
+
+// staticIPResolver always resolves to a fixed, pre-configured ip address
+type staticIPResolver struct {
+	ip string
+}
+
+// StaticIPResolver returns a resolver that always resolves to `ip`,
+// useful when the external ip is already known (e.g. a cloud provider's
+// elastic ip) and shouldn't be looked up over the network
+func StaticIPResolver(ip string) IPResolver {
+	return &staticIPResolver{ip: ip}
+}
+
+func (r *staticIPResolver) Name() string {
+	return "static"
+}
+
+func (r *staticIPResolver) ResolveIP(_ context.Context, _ IPFamily) (string, error) {
+	return r.ip, nil
+}
+
+// openDNSResolver resolves this host's external ip by asking OpenDNS's own
+// resolver for `myip.opendns.com`, the same trick `dig` users rely on
+type openDNSResolver struct{}
+
+// OpenDNSResolver returns a resolver backed by OpenDNS's `myip.opendns.com` trick
+func OpenDNSResolver() IPResolver {
+	return &openDNSResolver{}
+}
+
+func (r *openDNSResolver) Name() string {
+	return "opendns"
+}
+
+func (r *openDNSResolver) ResolveIP(ctx context.Context, family IPFamily) (string, error) {
+	resolver := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			d := net.Dialer{Timeout: 5 * time.Second}
+			return d.DialContext(ctx, network, "resolver1.opendns.com:53")
+		},
+	}
+
+	network := "ip4"
+	if family == IPv6 {
+		network = "ip6"
+	}
+
+	ips, err := resolver.LookupIP(ctx, network, "myip.opendns.com")
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve external ip via opendns: %s", err)
+	}
+	if len(ips) <= 0 {
+		return "", fmt.Errorf("opendns returned no ip addresses")
+	}
+
+	return ips[0].String(), nil
+}
+
+// localInterfaceResolver inspects a named network interface for its
+// globally-routable address
+type localInterfaceResolver struct {
+	interfaceName string
+}
+
+// LocalInterfaceResolver returns a resolver that inspects the named network
+// interface (e.g. "eth0") for its public address, for hosts that are
+// directly assigned a public ip rather than sitting behind NAT
+func LocalInterfaceResolver(interfaceName string) IPResolver {
+	return &localInterfaceResolver{interfaceName: interfaceName}
+}
+
+func (r *localInterfaceResolver) Name() string {
+	return fmt.Sprintf("local-interface(%s)", r.interfaceName)
+}
+
+func (r *localInterfaceResolver) ResolveIP(_ context.Context, family IPFamily) (string, error) {
+	iface, err := net.InterfaceByName(r.interfaceName)
+	if err != nil {
+		return "", fmt.Errorf("failed to find interface: %s: %s", r.interfaceName, err)
+	}
+
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return "", fmt.Errorf("failed to list addresses of interface: %s: %s", r.interfaceName, err)
+	}
+
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok {
+			continue
+		}
+
+		ip := ipNet.IP
+		isV4 := ip.To4() != nil
+		if (family == IPv4) != isV4 {
+			continue
+		}
+		if ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() {
+			continue
+		}
+
+		return ip.String(), nil
+	}
+
+	return "", fmt.Errorf("no public %s address found on interface: %s", family, r.interfaceName)
+}
+
+// chainResolver tries each resolver in order, returning the first success
+type chainResolver struct {
+	resolvers []IPResolver
+}
+
+// ChainResolver tries each of `resolvers` in order, falling back to the next
+// one on error, and returns the first successfully-resolved ip address
+func ChainResolver(resolvers ...IPResolver) IPResolver {
+	return &chainResolver{resolvers: resolvers}
+}
+
+func (r *chainResolver) Name() string {
+	return "chain"
+}
+
+func (r *chainResolver) ResolveIP(ctx context.Context, family IPFamily) (string, error) {
+	var lastErr error
+
+	for _, resolver := range r.resolvers {
+		ip, err := resolver.ResolveIP(ctx, family)
+		if err == nil && ip != "" {
+			return ip, nil
+		}
+
+		logger.Warnf("resolver: %s failed to resolve external ip: %s", resolver.Name(), err)
+		lastErr = err
+	}
+
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no resolvers configured")
+	}
+
+	return "", lastErr
+}
+
+// majorityResolver queries every resolver and returns the most commonly
+// returned ip address
+type majorityResolver struct {
+	resolvers []IPResolver
+}
+
+// MajorityResolver queries every one of `resolvers` and returns the ip
+// address returned by a majority of them, guarding against a single
+// provider returning a stale or incorrect address
+func MajorityResolver(resolvers ...IPResolver) IPResolver {
+	return &majorityResolver{resolvers: resolvers}
+}
+
+func (r *majorityResolver) Name() string {
+	return "majority-vote"
+}
+
+func (r *majorityResolver) ResolveIP(ctx context.Context, family IPFamily) (string, error) {
+	votes := map[string]int{}
+
+	for _, resolver := range r.resolvers {
+		ip, err := resolver.ResolveIP(ctx, family)
+		if err != nil {
+			logger.Warnf("resolver: %s failed to resolve external ip: %s", resolver.Name(), err)
+			continue
+		}
+
+		votes[ip]++
+	}
+
+	var winner string
+	var winnerVotes int
+	for ip, count := range votes {
+		if count > winnerVotes {
+			winner, winnerVotes = ip, count
+		}
+	}
+
+	if winner == "" {
+		return "", fmt.Errorf("no resolver returned an ip address")
+	}
+
+	return winner, nil
+}
+
+// DefaultResolver is the resolver chain used when no explicit
+// configuration of resolvers is given
+func DefaultResolver() IPResolver {
+	return ChainResolver(
+		GoogleCheckIPResolver(),
+		IcanhazipResolver(),
+		IpifyResolver(),
+	)
+}
+
+// activeResolver is the resolver used by GetExternalIP / GetExternalIPv6;
+// switch it with SetResolverMode (e.g. from the `--resolver-mode` flag)
+var activeResolver = DefaultResolver()
+
+// SetResolverMode selects how this host's external ip is discovered:
+//
+//	chain               - try google-checkip, icanhazip, and ipify in order,
+//	                      falling back on failure (the default)
+//	majority            - query all three and use the address most of them
+//	                      agree on, guarding against a single provider
+//	                      returning a stale or incorrect address
+//	opendns             - ask OpenDNS's own resolver for myip.opendns.com
+//	static:<ip>         - always resolve to the given, pre-configured ip
+//	interface:<name>    - read the public address off a local network
+//	                      interface (e.g. "interface:eth0"), for hosts
+//	                      directly assigned a public ip rather than behind NAT
+func SetResolverMode(mode string) error {
+	services := []IPResolver{
+		GoogleCheckIPResolver(),
+		IcanhazipResolver(),
+		IpifyResolver(),
+	}
+
+	switch {
+	case mode == "" || mode == "chain":
+		activeResolver = ChainResolver(services...)
+	case mode == "majority":
+		activeResolver = MajorityResolver(services...)
+	case mode == "opendns":
+		activeResolver = OpenDNSResolver()
+	case strings.HasPrefix(mode, "static:"):
+		ip := strings.TrimPrefix(mode, "static:")
+		if ip == "" {
+			return fmt.Errorf("static resolver mode requires an ip: static:<ip>")
+		}
+		activeResolver = StaticIPResolver(ip)
+	case strings.HasPrefix(mode, "interface:"):
+		name := strings.TrimPrefix(mode, "interface:")
+		if name == "" {
+			return fmt.Errorf("interface resolver mode requires a name: interface:<name>")
+		}
+		activeResolver = LocalInterfaceResolver(name)
+	default:
+		return fmt.Errorf("unknown resolver mode: %s (want \"chain\", \"majority\", \"opendns\", \"static:<ip>\", or \"interface:<name>\")", mode)
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package helper
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/zalando/go-keyring"
+)
+
+// resolveSecret resolves a credential value that may be given directly in
+// plaintext in config.json, or indirectly via one of:
+//
+//	env:VAR_NAME             - read from an environment variable
+//	file:/path/to/file       - read from a file's contents (trimmed)
+//	keyring:service/account  - read from the OS keyring
+//	op://vault/item/field    - read via the 1Password CLI (`op read`)
+func resolveSecret(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, "env:"):
+		name := strings.TrimPrefix(value, "env:")
+
+		v, exists := os.LookupEnv(name)
+		if !exists {
+			return "", fmt.Errorf("environment variable not set: %s", name)
+		}
+
+		return v, nil
+
+	case strings.HasPrefix(value, "file:"):
+		path := strings.TrimPrefix(value, "file:")
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read secret file: %s: %s", path, err)
+		}
+
+		return strings.TrimSpace(string(data)), nil
+
+	case strings.HasPrefix(value, "keyring:"):
+		ref := strings.TrimPrefix(value, "keyring:")
+
+		comps := strings.SplitN(ref, "/", 2)
+		if len(comps) != 2 {
+			return "", fmt.Errorf("keyring reference must be of the form keyring:service/account: %s", value)
+		}
+
+		v, err := keyring.Get(comps[0], comps[1])
+		if err != nil {
+			return "", fmt.Errorf("failed to read from keyring: %s", err)
+		}
+
+		return v, nil
+
+	case strings.HasPrefix(value, "op://"):
+		out, err := exec.Command("op", "read", value).Output()
+		if err != nil {
+			return "", fmt.Errorf("failed to read secret from 1password: %s", err)
+		}
+
+		return strings.TrimSpace(string(out)), nil
+
+	default:
+		return value, nil
+	}
+}
+
+// resolveConfigSecrets resolves every indirection-capable credential field of confs in place
+func resolveConfigSecrets(confs *Configs) error {
+	for i := range confs.Configs {
+		conf := &confs.Configs[i]
+
+		resolved, err := resolveSecret(conf.Username)
+		if err != nil {
+			return fmt.Errorf("failed to resolve username for hostname: %s: %s", conf.Hostname, err)
+		}
+		conf.Username = resolved
+
+		if resolved, err = resolveSecret(conf.Password); err != nil {
+			return fmt.Errorf("failed to resolve password for hostname: %s: %s", conf.Hostname, err)
+		}
+		conf.Password = resolved
+
+		if resolved, err = resolveSecret(conf.APIToken); err != nil {
+			return fmt.Errorf("failed to resolve api_token for hostname: %s: %s", conf.Hostname, err)
+		}
+		conf.APIToken = resolved
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package helper
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveSecretPlaintext(t *testing.T) {
+	got, err := resolveSecret("plain-value")
+	if err != nil {
+		t.Fatalf("resolveSecret() error: %s", err)
+	}
+	if got != "plain-value" {
+		t.Errorf("resolveSecret() = %q, want: %q", got, "plain-value")
+	}
+}
+
+func TestResolveSecretEnv(t *testing.T) {
+	t.Setenv("GOOGLE_DDNS_TEST_SECRET", "from-env")
+
+	got, err := resolveSecret("env:GOOGLE_DDNS_TEST_SECRET")
+	if err != nil {
+		t.Fatalf("resolveSecret() error: %s", err)
+	}
+	if got != "from-env" {
+		t.Errorf("resolveSecret() = %q, want: %q", got, "from-env")
+	}
+}
+
+func TestResolveSecretEnvMissing(t *testing.T) {
+	if _, err := resolveSecret("env:GOOGLE_DDNS_TEST_SECRET_UNSET"); err == nil {
+		t.Fatal("expected an error for an unset environment variable, got nil")
+	}
+}
+
+func TestResolveSecretFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secret")
+	if err := os.WriteFile(path, []byte("from-file\n"), 0600); err != nil {
+		t.Fatalf("failed to write test file: %s", err)
+	}
+
+	got, err := resolveSecret("file:" + path)
+	if err != nil {
+		t.Fatalf("resolveSecret() error: %s", err)
+	}
+	if got != "from-file" {
+		t.Errorf("resolveSecret() = %q, want: %q", got, "from-file")
+	}
+}
+
+func TestResolveSecretFileMissing(t *testing.T) {
+	if _, err := resolveSecret("file:" + filepath.Join(t.TempDir(), "does-not-exist")); err == nil {
+		t.Fatal("expected an error for a missing secret file, got nil")
+	}
+}
+
+func TestResolveSecretKeyringMalformed(t *testing.T) {
+	if _, err := resolveSecret("keyring:no-slash-here"); err == nil {
+		t.Fatal("expected an error for a malformed keyring reference, got nil")
+	}
+}
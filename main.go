@@ -15,15 +15,26 @@ package main
 //   0 6 * * * /path/to/google-ddns-updater -c /where/is/config.json
 //   0 7 * * * /path/to/google-ddns-updater -c /where/is/config.json some.domain.com
 //   0 8 * * * /path/to/google-ddns-updater -c /where/is/config.json another.domain.com andanother.domain.com
+//
+// daemon mode example (replaces the cronjob above with a single long-running process):
+//
+//   /path/to/google-ddns-updater -d --interval 600 -c /where/is/config.json
 
 import (
-	"log"
+	"context"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"time"
 
 	"github.com/meinside/google-ddns-updater/helper"
+	"github.com/meinside/google-ddns-updater/helper/metrics"
 )
 
+// default interval between external ip checks when running with `-d`/`--daemon`
+const defaultDaemonInterval = 10 * time.Minute
+
 func main() {
 	var confs helper.Configs
 	var err error
@@ -31,26 +42,82 @@ func main() {
 	// command line arguments
 	args := os.Args[1:]
 
+	// `encrypt-config` subcommand: encrypt a plaintext config file at rest
+	if len(args) > 0 && args[0] == "encrypt-config" {
+		if len(args) != 3 {
+			fmt.Println("usage: google-ddns-updater encrypt-config /path/to/config.json /path/to/config.json.enc")
+			os.Exit(1)
+		}
+
+		if err := helper.EncryptConfig(args[1], args[2]); err != nil {
+			helper.ExitWithError("failed to encrypt configs file: %s", err)
+		}
+
+		fmt.Printf("encrypted configs file written to: %s\n", args[2])
+
+		return
+	}
+
 	// read params from arguments
-	var needIP, needConf bool
-	var ipAddr string
+	var needIP, needConf, needInterval, needMetricsAddr, needCacheDir, needCacheBackend, needResolverMode, needLogFormat bool
+	var daemonMode bool
+	var ipAddr, metricsAddr, cacheDirFlag, cacheBackend, resolverMode, logFormat string
+	interval := defaultDaemonInterval
 	hostnames := []string{}
 	confFilepath := helper.DefaultConfFilepath()
 	for _, arg := range args {
 		if arg == "-h" || arg == "--help" { // help flag
 			helper.ExitWithHelpMessage()
 		} else if arg == "-i" || arg == "--ip" { // ip flag
-			if needConf { // wrong param was given
+			if needConf || needInterval || needMetricsAddr || needCacheDir || needCacheBackend || needResolverMode || needLogFormat { // wrong param was given
 				helper.ExitWithHelpMessage()
 			}
 
 			needIP = true
 		} else if arg == "-c" || arg == "--config" { // configs flag
-			if needIP { // wrong param was given
+			if needIP || needInterval || needMetricsAddr || needCacheDir || needCacheBackend || needResolverMode || needLogFormat { // wrong param was given
 				helper.ExitWithHelpMessage()
 			}
 
 			needConf = true
+		} else if arg == "-d" || arg == "--daemon" { // daemon flag
+			daemonMode = true
+		} else if arg == "--interval" { // daemon interval flag (seconds)
+			if needIP || needConf || needMetricsAddr || needCacheDir || needCacheBackend || needResolverMode || needLogFormat { // wrong param was given
+				helper.ExitWithHelpMessage()
+			}
+
+			needInterval = true
+		} else if arg == "--metrics-addr" { // metrics server flag
+			if needIP || needConf || needInterval || needCacheDir || needCacheBackend || needResolverMode || needLogFormat { // wrong param was given
+				helper.ExitWithHelpMessage()
+			}
+
+			needMetricsAddr = true
+		} else if arg == "--cache-dir" { // ip cache directory flag
+			if needIP || needConf || needInterval || needMetricsAddr || needCacheBackend || needResolverMode || needLogFormat { // wrong param was given
+				helper.ExitWithHelpMessage()
+			}
+
+			needCacheDir = true
+		} else if arg == "--cache-backend" { // ip cache backend flag ("file" or "sqlite")
+			if needIP || needConf || needInterval || needMetricsAddr || needCacheDir || needResolverMode || needLogFormat { // wrong param was given
+				helper.ExitWithHelpMessage()
+			}
+
+			needCacheBackend = true
+		} else if arg == "--resolver-mode" { // ip resolver mode flag ("chain", "majority", "opendns", "static:<ip>", or "interface:<name>")
+			if needIP || needConf || needInterval || needMetricsAddr || needCacheDir || needCacheBackend || needLogFormat { // wrong param was given
+				helper.ExitWithHelpMessage()
+			}
+
+			needResolverMode = true
+		} else if arg == "--log-format" { // log output format flag ("text", "quiet", "json", or "syslog")
+			if needIP || needConf || needInterval || needMetricsAddr || needCacheDir || needCacheBackend || needResolverMode { // wrong param was given
+				helper.ExitWithHelpMessage()
+			}
+
+			needLogFormat = true
 		} else if needIP {
 			ipAddr = arg
 
@@ -59,21 +126,87 @@ func main() {
 			confFilepath = arg
 
 			needConf = false
+		} else if needInterval { // daemon interval value
+			secs, convErr := strconv.Atoi(arg)
+			if convErr != nil || secs <= 0 {
+				helper.ExitWithHelpMessage()
+			}
+			interval = time.Duration(secs) * time.Second
+
+			needInterval = false
+		} else if needMetricsAddr { // metrics server address, eg. ":9090"
+			metricsAddr = arg
+
+			needMetricsAddr = false
+		} else if needCacheDir { // ip cache directory
+			cacheDirFlag = arg
+
+			needCacheDir = false
+		} else if needCacheBackend { // ip cache backend
+			cacheBackend = arg
+
+			needCacheBackend = false
+		} else if needResolverMode { // ip resolver mode
+			resolverMode = arg
+
+			needResolverMode = false
+		} else if needLogFormat { // log output format
+			logFormat = arg
+
+			needLogFormat = false
 		} else { // hostnames
 			hostnames = append(hostnames, arg)
 		}
 	}
-	if needIP || needConf { // needed params were not given
+	if needIP || needConf || needInterval || needMetricsAddr || needCacheDir || needCacheBackend || needResolverMode || needLogFormat { // needed params were not given
 		helper.ExitWithHelpMessage()
 	}
 
+	if err = helper.SetLogFormat(logFormat); err != nil {
+		helper.ExitWithError("invalid --log-format: %s", err)
+	}
+
+	ctx := context.Background()
+
+	// optional prometheus metrics + healthcheck server
+	if metricsAddr != "" {
+		metrics.StartServer(metricsAddr)
+		helper.Infof("serving metrics and healthcheck on: %s", metricsAddr)
+	}
+
 	// load configs
 	if confs, err = helper.ReadConfigs(confFilepath); err == nil {
-		log.Printf("loaded configs file at: %s", confFilepath)
+		helper.Infof("loaded configs file at: %s", confFilepath)
 	} else {
 		helper.ExitWithError("failed to read configs file at: %s", confFilepath)
 	}
 
+	cacheDir := cacheDirFlag
+	if cacheDir == "" {
+		cacheDir = filepath.Dir(confFilepath)
+	}
+
+	if err = helper.SetCacheBackend(cacheBackend); err != nil {
+		helper.ExitWithError("invalid --cache-backend: %s", err)
+	}
+
+	if err = helper.SetResolverMode(resolverMode); err != nil {
+		helper.ExitWithError("invalid --resolver-mode: %s", err)
+	}
+
+	// notify configured sinks (webhook/slack/discord/telegram/shell) of update events
+	if notifier, notifierErr := helper.BuildNotifier(confs); notifierErr == nil {
+		helper.SetNotifier(notifier)
+	} else {
+		helper.Errorf("failed to build notifier from configs: %s", notifierErr)
+	}
+
+	// run forever, re-checking on every interval, instead of relying on cron
+	if daemonMode {
+		runDaemon(ctx, confFilepath, hostnames, cacheDir, interval)
+		return
+	}
+
 	// if no hosts were given,
 	if len(hostnames) <= 0 {
 		// load all hosts from configs
@@ -89,44 +222,70 @@ func main() {
 
 	// if ip address was not in the configs, fetch it from google domains
 	if ipAddr == "" {
-		if ipAddr, err = helper.GetExternalIP(); err == nil {
-			log.Printf("fetched external ip: %s", ipAddr)
+		if ipAddr, err = helper.GetExternalIP(ctx); err == nil {
+			helper.Infof("fetched external ip: %s", ipAddr)
 		}
 	}
 
 	// will not work without an ip address...
 	if ipAddr != "" {
-		cacheDir := filepath.Dir(confFilepath)
+		err = updateHostnames(ctx, confs, hostnames, cacheDir, ipAddr)
+	}
 
-		for _, hostname := range hostnames {
-			log.Printf("processing hostname: %s", hostname)
+	// give fired notifications (and their retries) a chance to land before
+	// this one-shot/cron invocation exits out from under them
+	helper.WaitForNotifications()
 
-			conf := helper.ConfigForHostname(confs, hostname)
-			if conf == nil {
-				log.Printf("no such hostname: %s in the configs", hostname)
-				continue
-			}
+	// check error
+	if err != nil {
+		helper.ExitWithError(err.Error())
+	}
+
+	helper.Infof("update finished")
+}
+
+// updateHostnames updates every hostname in `hostnames` (or every hostname in
+// `confs` when `hostnames` is empty) to `ipAddr` (used for the "A" record),
+// fetching a separate ipv6 address for hostnames configured with "AAAA" or
+// "both", and skipping records whose cached ip in `cacheDir` is already up to date
+func updateHostnames(ctx context.Context, confs helper.Configs, hostnames []string, cacheDir, ipAddr string) error {
+	var err error
+
+	for _, hostname := range hostnames {
+		helper.Debugf("processing hostname: %s", hostname)
+
+		conf := helper.ConfigForHostname(confs, hostname)
+		if conf == nil {
+			helper.Warnf("no such hostname: %s in the configs", hostname)
+			continue
+		}
+
+		for _, recordType := range conf.RecordTypes() {
+			family := helper.IPv4
+			wantIP := ipAddr
 
-			// read cached ip address,
-			var savedIP string
-			if savedIP, err = helper.LoadCachedIP(*conf, cacheDir); err == nil {
-				if ipAddr != savedIP {
-					if updateErr := helper.UpdateIP(*conf, cacheDir, ipAddr); updateErr != nil {
-						err = updateErr
-
-						log.Printf("failed to update ip: %s for hostname: %s (%s)", ipAddr, conf.Hostname, err)
-					}
-				} else {
-					log.Printf("cached ip address: %s is already set for hostname: %s", savedIP, conf.Hostname)
+			if recordType == "AAAA" {
+				family = helper.IPv6
+
+				if wantIP, err = helper.GetExternalIPv6(ctx); err != nil {
+					helper.Errorf("failed to fetch external ipv6 address for hostname: %s (%s)", conf.Hostname, err)
+					continue
 				}
 			}
-		}
-	}
 
-	// check error
-	if err != nil {
-		helper.ExitWithError(err.Error())
+			// compare against the cached ip address and, if it changed,
+			// push the update — all under one cache lock, so this can't
+			// race an overlapping invocation for the same hostname
+			changed, savedIP, syncErr := helper.SyncIP(ctx, *conf, cacheDir, family, wantIP)
+			if syncErr != nil {
+				err = syncErr
+
+				helper.Errorf("failed to update %s record: %s for hostname: %s (%s)", recordType, wantIP, conf.Hostname, err)
+			} else if !changed {
+				helper.Debugf("cached %s address: %s is already set for hostname: %s", recordType, savedIP, conf.Hostname)
+			}
+		}
 	}
 
-	log.Printf("update finished")
+	return err
 }